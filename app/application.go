@@ -3,24 +3,44 @@ package app
 import (
 	"clipboard/clipboard"
 	"clipboard/config"
+	"clipboard/hotkey"
+	"clipboard/httpapi"
+	"clipboard/imageproc"
+	"clipboard/instance"
+	"clipboard/ipc"
+	"clipboard/model"
 	"clipboard/storage"
 	"clipboard/ui"
+	"errors"
+	"fmt"
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"log"
+	"sync"
+	"time"
 )
 
 // Application 应用程序核心
 type Application struct {
-	fyneApp fyne.App
-	config  *config.AppConfig
-	storage storage.Storage
-	monitor *clipboard.Monitor
-	window  *ui.Window
+	fyneApp     fyne.App
+	config      *config.AppConfig
+	storage     storage.Storage
+	monitor     capturer
+	remote      *ipc.RemoteMonitor    // 连接到后台服务时非空，捕获由后台服务负责
+	api         *httpapi.Server       // 本地 HTTP 接口，未启用时为空
+	inst        *instance.Instance    // 单实例锁，为空时不接收其他进程转发的命令
+	hotkey      *hotkey.Hotkey        // 已注册的全局快捷键，未注册或退回为窗口内快捷键时为空
+	queueHotkey *hotkey.Hotkey        // 粘贴队列进行中时注册的“载入下一项”全局快捷键
+	queueCombo  hotkey.Combo          // 粘贴队列快捷键应注册的组合，注册失败时不重复尝试
+	capture     *instance.CaptureLock // 本地捕获时持有的捕获锁
+	server      *ipc.Server           // 本地捕获时为命令行提供的接口，与后台服务相同
+	mu          sync.RWMutex          // 保护设置保存后被替换的存储与监听器（供 server 使用）
+	window      *ui.Window
 }
 
-// New 创建应用实例（保持原逻辑）
-func New() (*Application, error) {
+// New 创建应用实例，后台服务在运行时界面作为其客户端，否则在本进程内捕获；
+// inst 非空时执行其他进程转发来的命令
+func New(inst *instance.Instance) (*Application, error) {
 	fyneApp := app.New()
 
 	// 加载配置
@@ -29,60 +49,151 @@ func New() (*Application, error) {
 		return nil, err
 	}
 
-	// 创建存储
-	store, err := storage.NewStorage(&cfg.Storage)
-	if err != nil {
-		return nil, err
+	app := &Application{
+		fyneApp: fyneApp,
+		config:  cfg,
+		inst:    inst,
 	}
 
-	// 创建剪贴板监听器
-	monitor, err := clipboard.NewMonitor(store)
+	client, err := ipc.Dial(ipc.SocketPath())
 	if err != nil {
-		return nil, err
+		// 捕获锁被占用说明后台服务刚启动、尚未开始监听，或命令行正在直接读写存储
+		app.capture, err = instance.LockCapture()
+		if errors.Is(err, instance.ErrRunning) {
+			if client, err = ipc.DialWait(ipc.SocketPath(), captureWait); err != nil {
+				return nil, fmt.Errorf("其他进程正在捕获剪贴板且无法连接: %w", err)
+			}
+		} else if err != nil {
+			log.Printf("获取捕获锁失败，继续在本进程内捕获: %v", err)
+		}
 	}
 
-	// 创建应用实例
-	app := &Application{
-		fyneApp: fyneApp,
-		config:  cfg,
-		storage: store,
-		monitor: monitor,
+	if client != nil {
+		log.Println("已连接到后台服务，界面作为客户端运行")
+		app.storage = ipc.NewRemoteStorage(client)
+		app.remote = ipc.NewRemoteMonitor(client)
+		app.monitor = app.remote
+	} else {
+		// 创建存储与剪贴板监听器
+		store, monitor, err := openLocal(cfg)
+		if err != nil {
+			app.capture.Release()
+			return nil, err
+		}
+		app.storage = store
+		app.monitor = monitor
+		app.api = startHTTPAPI(cfg, store)
+		app.serveIPC()
 	}
 
-	// 创建主窗口
-	app.window = ui.NewWindow(fyneApp, store, monitor, app.handleSaveSettings)
+	// 创建主窗口，启用托盘时关闭窗口只最小化到托盘
+	app.window = ui.NewWindow(fyneApp, app.storage, app, app.handleSaveSettings)
+	if cfg.Tray.Enabled && !app.window.SetupTray(cfg.Tray.RecentItems) {
+		log.Println("当前平台不支持系统托盘")
+	}
+	app.registerHotkey()
 
 	// 设置剪贴板监听器
 	app.setupClipboardListener()
 
+	// 为旧版本记录的图片补全元数据（后台服务启动时已自行补全）
+	if app.remote == nil {
+		go func() {
+			if backfillImageMeta(app.storage) > 0 {
+				fyne.Do(func() {
+					app.window.UpdateHistory(nil)
+				})
+			}
+		}()
+	}
+
+	if inst != nil {
+		go app.receiveCommands()
+	}
+
 	return app, nil
 }
 
+// receiveCommands 在界面线程中执行其他进程转发来的命令
+func (a *Application) receiveCommands() {
+	for cmd := range a.inst.Commands() {
+		log.Printf("收到转发的命令: %s", cmd.Action)
+		fyne.Do(func() {
+			a.Execute(cmd)
+		})
+	}
+}
+
+// Execute 执行启动命令：显示窗口、搜索、打开快速粘贴或将指定项写入剪贴板
+func (a *Application) Execute(cmd instance.Command) {
+	switch cmd.Action {
+	case instance.ActionShow:
+		a.window.ShowSearch("")
+	case instance.ActionSearch:
+		a.window.ShowSearch(cmd.Query)
+	case instance.ActionPick:
+		a.window.ShowQuickPaste()
+	case instance.ActionPaste:
+		if err := a.pasteByID(cmd.ID); err != nil {
+			log.Printf("粘贴 %s 失败: %v", cmd.ID, err)
+		}
+	}
+}
+
+// pasteByID 按 ID 或唯一的 ID 前缀将项写入剪贴板
+func (a *Application) pasteByID(id string) error {
+	items, err := a.storage.LoadItems()
+	if err != nil {
+		return err
+	}
+	item, err := model.FindByIDPrefix(items, id)
+	if err != nil {
+		return err
+	}
+	return a.SetContent(item)
+}
+
 // Run 运行应用（保持原逻辑）
 func (a *Application) Run() {
 	a.window.ShowAndRun()
+	a.hotkey.Unregister()
+	a.queueHotkey.Unregister()
+	if a.inst != nil {
+		a.inst.Release()
+	}
+	if a.server != nil {
+		a.server.Close()
+	}
+	a.api.Close()
 	a.storage.Close()
 	a.monitor.Stop()
+	a.capture.Release()
 }
 
-// 设置剪贴板监听器（修改为触发全量重建）
+// 设置剪贴板监听器，捕获后刷新界面
 func (a *Application) setupClipboardListener() {
 	// 启动剪贴板监控
-	if err := a.monitor.Start(); err != nil {
+	monitor := a.monitor
+	if err := monitor.Start(); err != nil {
 		log.Printf("启动剪贴板监控失败: %v", err)
 		return
 	}
 
-	// 监听剪贴板变化（触发UI全量重建）
+	// 监听剪贴板变化
 	go func() {
 		for {
 			select {
-			case <-a.monitor.ChangeChan():
-				log.Println("应用层收到剪贴板变化，触发UI全量重建")
+			case items := <-monitor.ChangeChan():
+				log.Println("应用层收到剪贴板变化，刷新界面")
+				a.api.Notify(items)
+				if a.server != nil {
+					a.server.Notify("capture")
+				}
 				fyne.Do(func() {
-					a.window.UpdateHistory(nil) // 空入参触发重建
+					a.window.UpdateHistory(nil)
+					a.syncQueueHotkey()
 				})
-			case <-a.monitor.StopChan:
+			case <-monitor.Done():
 				log.Println("剪贴板监听协程退出")
 				return
 			}
@@ -90,33 +201,152 @@ func (a *Application) setupClipboardListener() {
 	}()
 }
 
-// 处理保存设置（修改为触发全量重建）
-func (a *Application) handleSaveSettings(newStorageCfg *config.StorageConfig) {
-	// 更新配置
-	a.config.Storage = *newStorageCfg
+// SetContent 将历史项写回剪贴板（转发给当前监听器，设置保存后监听器会重建）
+func (a *Application) SetContent(item *model.ClipboardItem) error {
+	return a.monitor.SetContentWith(item, clipboard.PasteOptions{})
+}
+
+// SetTransformedContent 按转换链处理文本后写回剪贴板
+func (a *Application) SetTransformedContent(item *model.ClipboardItem, steps []string) error {
+	return a.monitor.SetContentWith(item, clipboard.PasteOptions{Transforms: steps})
+}
+
+// SetSnippetContent 展开片段占位符后写回剪贴板
+func (a *Application) SetSnippetContent(item *model.ClipboardItem, inputs map[string]string) error {
+	return a.monitor.SetContentWith(item, clipboard.PasteOptions{Inputs: inputs})
+}
+
+// SetConvertedImage 按选项转换图片后写回剪贴板
+func (a *Application) SetConvertedImage(item *model.ClipboardItem, opts imageproc.Options) error {
+	return a.monitor.SetContentWith(item, clipboard.PasteOptions{Image: opts})
+}
+
+// StartPasteQueue 启动粘贴队列，lifo 为 true 时从最后选中的项开始
+func (a *Application) StartPasteQueue(items []*model.ClipboardItem, lifo bool) error {
+	mode := clipboard.QueueFIFO
+	if lifo {
+		mode = clipboard.QueueLIFO
+	}
+	return a.monitor.StartQueue(items, mode)
+}
+
+// AdvancePasteQueue 载入粘贴队列的下一项，队列已空时不视为错误
+func (a *Application) AdvancePasteQueue() error {
+	if err := a.monitor.AdvanceQueue(); err != nil && !errors.Is(err, clipboard.ErrQueueEmpty) {
+		return err
+	}
+	return nil
+}
+
+// ClearPasteQueue 结束粘贴队列
+func (a *Application) ClearPasteQueue() {
+	a.monitor.ClearQueue()
+}
+
+// PasteQueue 返回粘贴队列的当前项和等待项
+func (a *Application) PasteQueue() (*model.ClipboardItem, []*model.ClipboardItem) {
+	return a.monitor.QueueState()
+}
+
+// SaveTransformPreset 保存转换预设，同名预设会被覆盖
+func (a *Application) SaveTransformPreset(preset config.TransformPreset) error {
+	for i, p := range a.config.TransformPresets {
+		if p.Name == preset.Name {
+			a.config.TransformPresets[i] = preset
+			return config.Save(a.config)
+		}
+	}
+	a.config.TransformPresets = append(a.config.TransformPresets, preset)
+	return config.Save(a.config)
+}
+
+// PauseCapture 暂停捕获，d 为 0 时直到手动恢复
+func (a *Application) PauseCapture(d time.Duration) {
+	a.monitor.Pause(d)
+	a.savePauseState()
+}
+
+// ResumeCapture 恢复捕获
+func (a *Application) ResumeCapture() {
+	a.monitor.Resume()
+	a.savePauseState()
+}
+
+// CapturePaused 返回是否暂停捕获及截止时间
+func (a *Application) CapturePaused() (bool, time.Time) {
+	return a.monitor.PauseState()
+}
+
+// savePauseState 按配置持久化暂停状态（后台服务会自行保存）
+func (a *Application) savePauseState() {
+	if a.remote == nil {
+		savePauseState(a.config, a.monitor)
+	}
+}
+
+// 处理保存设置，本地捕获时按新配置重建存储与监听器
+func (a *Application) handleSaveSettings(newCfg *config.AppConfig) {
+	// 更新配置；设置面板只编辑自己的字段，转换预设以当前配置为准（面板中的是创建时的副本），
+	// 暂停状态以运行中的监听器为准
+	presets := a.config.TransformPresets
+	*a.config = *newCfg
+	a.config.TransformPresets = presets
+	paused, until := a.monitor.PauseState()
+	a.config.CapturePause.Paused, a.config.CapturePause.Until = paused, until
 	config.Save(a.config)
+	a.registerHotkey()
+	if a.config.Tray.Enabled {
+		a.window.SetupTray(a.config.Tray.RecentItems)
+	}
+
+	// 连接到后台服务时由其重新加载配置
+	if a.remote != nil {
+		if err := a.remote.Reload(); err != nil {
+			log.Printf("后台服务重新加载配置失败: %v", err)
+		}
+		fyne.Do(func() {
+			a.window.UpdateHistory(nil)
+		})
+		return
+	}
+	a.mu.Lock()
+	err := a.reopenLocal(paused, until)
+	a.mu.Unlock()
+	if err != nil {
+		log.Printf("%v", err)
+		return
+	}
+	a.setupClipboardListener()
+
+	// 界面与快速粘贴窗口改用新存储
+	log.Println("设置保存完成，刷新界面")
+	a.window.SetStorage(a.storage)
+}
 
+// reopenLocal 按新配置重建存储、HTTP 接口与监听器，调用方持有 a.mu
+func (a *Application) reopenLocal(paused bool, until time.Time) error {
 	// 停止当前监听器
 	a.monitor.Stop()
 
-	// 关闭当前存储
+	// 关闭当前 HTTP 接口与存储
+	a.api.Close()
+	a.api = nil
 	a.storage.Close()
 
 	// 重建存储实例
-	newStorage, err := storage.NewStorage(newStorageCfg)
+	newStorage, err := storage.NewStorage(&a.config.Storage)
 	if err != nil {
-		log.Printf("重建存储失败: %v", err)
-		return
+		return fmt.Errorf("重建存储失败: %w", err)
 	}
 	a.storage = newStorage
+	a.api = startHTTPAPI(a.config, newStorage)
 
 	// 重建监听器实例
-	a.monitor, _ = clipboard.NewMonitor(newStorage)
-	a.setupClipboardListener()
-
-	// 触发UI全量重建
-	log.Println("设置保存完成，触发UI全量重建")
-	fyne.Do(func() {
-		a.window.UpdateHistory(nil)
-	})
+	monitor, err := clipboard.NewMonitor(newStorage, a.config)
+	if err != nil {
+		return fmt.Errorf("重建监听器失败: %w", err)
+	}
+	a.monitor = monitor
+	restorePause(a.monitor, paused, until)
+	return nil
 }
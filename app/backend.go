@@ -0,0 +1,114 @@
+package app
+
+import (
+	"clipboard/clipboard"
+	"clipboard/config"
+	"clipboard/httpapi"
+	"clipboard/imagemeta"
+	"clipboard/model"
+	"clipboard/storage"
+	"log"
+	"time"
+)
+
+// capturer 负责捕获与写回剪贴板的监听器，本地运行时为 clipboard.Monitor，
+// 连接到后台服务时为 ipc.RemoteMonitor
+type capturer interface {
+	Start() error
+	Stop()
+	Done() <-chan struct{}
+	ChangeChan() <-chan []*model.ClipboardItem
+	SetContentWith(item *model.ClipboardItem, opts clipboard.PasteOptions) error
+	Pause(d time.Duration)
+	Resume()
+	PauseState() (bool, time.Time)
+	StartQueue(items []*model.ClipboardItem, mode clipboard.QueueMode) error
+	AdvanceQueue() error
+	ClearQueue()
+	QueueState() (*model.ClipboardItem, []*model.ClipboardItem)
+}
+
+// openLocal 按配置创建存储和本地监听器
+func openLocal(cfg *config.AppConfig) (storage.Storage, *clipboard.Monitor, error) {
+	store, err := storage.NewStorage(&cfg.Storage)
+	if err != nil {
+		return nil, nil, err
+	}
+	monitor, err := clipboard.NewMonitor(store, cfg)
+	if err != nil {
+		store.Close()
+		return nil, nil, err
+	}
+	return store, monitor, nil
+}
+
+// startHTTPAPI 按配置启动本地 HTTP 接口，未启用或启动失败时返回 nil
+func startHTTPAPI(cfg *config.AppConfig, store storage.Storage) *httpapi.Server {
+	if !cfg.HTTPAPI.Enabled {
+		return nil
+	}
+	api, err := httpapi.NewServer(cfg, store)
+	if err == nil {
+		err = api.Start()
+	}
+	if err != nil {
+		log.Printf("启动 HTTP 接口失败: %v", err)
+		return nil
+	}
+	return api
+}
+
+// restorePause 重建监听器后恢复原有的暂停状态（已过截止时间的除外）
+func restorePause(m capturer, paused bool, until time.Time) {
+	if !paused || (!until.IsZero() && !time.Now().Before(until)) {
+		return
+	}
+	var remaining time.Duration
+	if !until.IsZero() {
+		remaining = time.Until(until)
+	}
+	m.Pause(remaining)
+}
+
+// savePauseState 按配置持久化暂停状态
+func savePauseState(cfg *config.AppConfig, m capturer) {
+	if !cfg.CapturePause.Persist {
+		return
+	}
+	cfg.CapturePause.Paused, cfg.CapturePause.Until = m.PauseState()
+	if err := config.Save(cfg); err != nil {
+		log.Printf("保存暂停状态失败: %v", err)
+	}
+}
+
+// backfillImageMeta 从图片文件中补全缺少元数据的图片项，返回补全的数量
+func backfillImageMeta(store storage.Storage) int {
+	items, err := store.LoadItems()
+	if err != nil {
+		log.Printf("加载历史记录失败，跳过图片元数据补全: %v", err)
+		return 0
+	}
+
+	metas := make(map[string]model.ImageMeta)
+	for _, item := range items {
+		if item.Type != model.TypeImage || item.ImagePath == "" || item.Width > 0 {
+			continue
+		}
+		meta, err := imagemeta.FromFile(item.ImagePath)
+		if err != nil {
+			log.Printf("补全图片元数据失败（%s）: %v", item.ImagePath, err)
+			continue
+		}
+		metas[item.ID] = meta
+	}
+	if len(metas) == 0 {
+		return 0
+	}
+
+	if err := store.SetImageMeta(metas); err != nil {
+		log.Printf("保存图片元数据失败: %v", err)
+		return 0
+	}
+	log.Printf("已补全 %d 个图片项的元数据", len(metas))
+	return len(metas)
+}
@@ -0,0 +1,164 @@
+package app
+
+import (
+	"clipboard/clipboard"
+	"clipboard/config"
+	"clipboard/httpapi"
+	"clipboard/instance"
+	"clipboard/ipc"
+	"clipboard/storage"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// daemon 后台服务：在没有界面的情况下捕获剪贴板，并通过本地套接字为界面和命令行提供接口
+type daemon struct {
+	mu      sync.RWMutex
+	config  *config.AppConfig
+	storage storage.Storage
+	monitor *clipboard.Monitor
+	server  *ipc.Server
+	api     *httpapi.Server // 本地 HTTP 接口，未启用时为空
+}
+
+// RunDaemon 运行后台服务，收到 SIGINT 或 SIGTERM 时退出
+func RunDaemon() error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	// 本地捕获的图形界面、另一个后台服务或直接写存储的命令行在运行时不能启动，
+	// 否则两个监听器会同时写入同一份历史记录
+	lock, err := instance.LockCapture()
+	if errors.Is(err, instance.ErrRunning) {
+		return errors.New("图形界面或另一个后台服务正在捕获剪贴板，请先退出它")
+	}
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	store, monitor, err := openLocal(cfg)
+	if err != nil {
+		return err
+	}
+	d := &daemon{config: cfg, storage: store, monitor: monitor}
+	defer func() {
+		d.mu.Lock()
+		d.api.Close()
+		d.monitor.Stop()
+		d.storage.Close()
+		d.mu.Unlock()
+	}()
+
+	path := ipc.SocketPath()
+	d.server, err = ipc.Listen(path, d)
+	if err != nil {
+		return err
+	}
+
+	d.api = startHTTPAPI(cfg, store)
+	if err := d.startMonitor(monitor); err != nil {
+		d.server.Close()
+		return fmt.Errorf("启动剪贴板监控失败: %w", err)
+	}
+	go func() {
+		if backfillImageMeta(store) > 0 {
+			d.server.Notify("backfill")
+		}
+	}()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		log.Printf("收到信号 %v，后台服务退出", sig)
+		d.server.Close()
+	}()
+
+	log.Printf("后台服务已启动，监听 %s", path)
+	return d.server.Serve()
+}
+
+// startMonitor 启动监听器并将捕获到的变化转发给订阅者
+func (d *daemon) startMonitor(m *clipboard.Monitor) error {
+	if err := m.Start(); err != nil {
+		return err
+	}
+	go func() {
+		for {
+			select {
+			case items := <-m.ChangeChan():
+				d.server.Notify("capture")
+				d.mu.RLock()
+				d.api.Notify(items)
+				d.mu.RUnlock()
+			case <-m.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Storage 当前存储
+func (d *daemon) Storage() storage.Storage {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.storage
+}
+
+// Monitor 当前监听器
+func (d *daemon) Monitor() *clipboard.Monitor {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.monitor
+}
+
+// Reload 重新读取配置并重建存储与监听器，暂停状态保持不变
+func (d *daemon) Reload() error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	paused, until := d.monitor.PauseState()
+	d.api.Close()
+	d.monitor.Stop()
+	d.storage.Close()
+
+	store, monitor, err := openLocal(cfg)
+	if err != nil {
+		// 重建失败时沿用原配置，避免服务停止捕获
+		var fallbackErr error
+		if store, monitor, fallbackErr = openLocal(d.config); fallbackErr != nil {
+			return fmt.Errorf("恢复原配置失败: %w", fallbackErr)
+		}
+		cfg = d.config
+		err = fmt.Errorf("应用新配置失败，已沿用原配置: %w", err)
+	}
+	d.config, d.storage, d.monitor = cfg, store, monitor
+	d.api = startHTTPAPI(cfg, store)
+	restorePause(monitor, paused, until)
+	if startErr := d.startMonitor(monitor); startErr != nil {
+		return fmt.Errorf("启动剪贴板监控失败: %w", startErr)
+	}
+	log.Println("后台服务已重新加载配置")
+	return err
+}
+
+// SavePauseState 按配置持久化暂停状态
+func (d *daemon) SavePauseState() {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	savePauseState(d.config, d.monitor)
+}
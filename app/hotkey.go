@@ -0,0 +1,85 @@
+package app
+
+import (
+	"clipboard/config"
+	"clipboard/hotkey"
+	"fyne.io/fyne/v2"
+	"log"
+)
+
+// registerHotkey 按配置注册全局快捷键，无法注册时退回为窗口内快捷键
+func (a *Application) registerHotkey() {
+	a.hotkey.Unregister()
+	a.hotkey = nil
+	a.window.SetHotkeyFallback(nil, nil)
+	if a.config.Hotkey == "" {
+		return
+	}
+
+	combo, err := hotkey.Parse(a.config.Hotkey)
+	if err != nil {
+		log.Printf("全局快捷键 %q 无效: %v", a.config.Hotkey, err)
+		return
+	}
+	hk, err := hotkey.Register(combo)
+	if err != nil {
+		log.Printf("%v，改为只在窗口内生效", err)
+		a.window.SetHotkeyFallback(combo.Shortcut(), a.triggerHotkey)
+		return
+	}
+	a.hotkey = hk
+	log.Printf("已注册全局快捷键 %s", combo)
+
+	go func() {
+		for range hk.Keydown() {
+			fyne.Do(a.triggerHotkey)
+		}
+	}()
+}
+
+// triggerHotkey 执行全局快捷键的动作
+func (a *Application) triggerHotkey() {
+	switch a.config.HotkeyAction {
+	case config.HotkeyActionWindow:
+		a.window.ShowSearch("")
+	default:
+		a.window.ShowQuickPaste()
+	}
+}
+
+// syncQueueHotkey 粘贴队列进行中时将“载入下一项”注册为全局快捷键，
+// 在其他程序中也能手动前进（无法检测粘贴的内容或环境需要）；队列结束后注销
+func (a *Application) syncQueueHotkey() {
+	var combo hotkey.Combo
+	if current, _ := a.monitor.QueueState(); current != nil {
+		combo, _ = hotkey.Parse(a.config.Shortcuts[config.ShortcutQueueNext])
+	}
+	if combo == a.queueCombo {
+		return
+	}
+	a.queueCombo = combo
+	a.queueHotkey.Unregister()
+	a.queueHotkey = nil
+	if combo.IsZero() {
+		return
+	}
+
+	hk, err := hotkey.Register(combo)
+	if err != nil {
+		// 窗口内快捷键仍然有效
+		log.Printf("%v，粘贴队列只能在窗口内载入下一项", err)
+		return
+	}
+	a.queueHotkey = hk
+	log.Printf("粘贴队列进行中，已注册全局快捷键 %s 载入下一项", combo)
+
+	go func() {
+		for range hk.Keydown() {
+			fyne.Do(func() {
+				if err := a.AdvancePasteQueue(); err != nil {
+					log.Printf("载入粘贴队列下一项失败: %v", err)
+				}
+			})
+		}
+	}()
+}
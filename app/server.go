@@ -0,0 +1,62 @@
+package app
+
+import (
+	"clipboard/clipboard"
+	"clipboard/config"
+	"clipboard/ipc"
+	"clipboard/storage"
+	"fyne.io/fyne/v2"
+	"log"
+	"time"
+)
+
+// captureWait 捕获锁被占用时等待对方开始监听的时间
+const captureWait = 3 * time.Second
+
+// serveIPC 本地捕获时在后台服务的套接字上提供同样的接口，
+// 命令行因此会通过本进程读写，而不是与界面同时直接写入存储
+func (a *Application) serveIPC() {
+	server, err := ipc.Listen(ipc.SocketPath(), a)
+	if err != nil {
+		log.Printf("启动本地接口失败，命令行将无法连接: %v", err)
+		return
+	}
+	a.server = server
+	go func() {
+		if err := server.Serve(); err != nil {
+			log.Printf("本地接口已停止: %v", err)
+		}
+	}()
+}
+
+// Storage 当前存储（ipc.Backend）
+func (a *Application) Storage() storage.Storage {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.storage
+}
+
+// Monitor 当前监听器（ipc.Backend），只在本地捕获时提供接口
+func (a *Application) Monitor() *clipboard.Monitor {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	monitor, _ := a.monitor.(*clipboard.Monitor)
+	return monitor
+}
+
+// Reload 重新读取配置并按保存设置的流程应用（ipc.Backend）
+func (a *Application) Reload() error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	fyne.DoAndWait(func() {
+		a.handleSaveSettings(cfg)
+	})
+	return nil
+}
+
+// SavePauseState 按配置持久化暂停状态（ipc.Backend）
+func (a *Application) SavePauseState() {
+	a.savePauseState()
+}
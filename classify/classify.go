@@ -0,0 +1,173 @@
+package classify
+
+import (
+	"clipboard/model"
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	hexColorRe = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3,4}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+	rgbColorRe = regexp.MustCompile(`^(?i)(rgba?|hsla?)\(\s*\d{1,3}%?\s*,\s*\d{1,3}%?\s*,\s*\d{1,3}%?\s*(,\s*[\d.]+\s*)?\)$`)
+	emailRe    = regexp.MustCompile(`^(?i)(mailto:)?[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}$`)
+	phoneRe    = regexp.MustCompile(`^\+?[\d\s().-]{7,20}$`)
+	dateRe     = regexp.MustCompile(`^\d{4}[-/.]\d{1,2}[-/.]\d{1,2}$`)
+	winPathRe  = regexp.MustCompile(`^[a-zA-Z]:[\\/]`)
+	sqlRe      = regexp.MustCompile(`(?is)^\s*(select\s.+\sfrom\s|insert\s+into\s|update\s+\S+\s+set\s|delete\s+from\s|create\s+(table|index|view|database)\s|alter\s+table\s|drop\s+(table|index|view|database)\s|with\s+\w+\s+as\s*\()`)
+	xmlRe      = regexp.MustCompile(`(?s)^<[!?a-zA-Z].*>$`)
+)
+
+// shellCommands 常见命令行程序，用于识别 Shell 命令
+var shellCommands = map[string]bool{
+	"sudo": true, "cd": true, "ls": true, "cat": true, "grep": true, "find": true,
+	"git": true, "docker": true, "kubectl": true, "curl": true, "wget": true,
+	"ssh": true, "scp": true, "rsync": true, "tar": true, "chmod": true, "chown": true,
+	"mkdir": true, "rm": true, "cp": true, "mv": true, "echo": true, "export": true,
+	"apt": true, "apt-get": true, "yum": true, "dnf": true, "brew": true, "pacman": true,
+	"npm": true, "npx": true, "yarn": true, "pnpm": true, "pip": true, "pip3": true,
+	"go": true, "cargo": true, "make": true, "systemctl": true, "journalctl": true,
+}
+
+// languageHints 源代码语言特征，命中越多越可能是该语言
+var languageHints = []struct {
+	language string
+	hints    []string
+}{
+	{"go", []string{"package ", "func ", ":= ", "fmt.", "err != nil", "import (", "go func"}},
+	{"python", []string{"def ", "import ", "self.", "elif ", "print(", "__init__", "from "}},
+	{"javascript", []string{"function ", "const ", "let ", "=> ", "console.log", "require(", "export "}},
+	{"typescript", []string{"interface ", ": string", ": number", "export type ", "as const", "readonly "}},
+	{"java", []string{"public class ", "private ", "System.out", "void ", "@Override", "new "}},
+	{"c", []string{"#include", "int main", "printf(", "malloc(", "->", "NULL"}},
+	{"cpp", []string{"#include", "std::", "cout <<", "template<", "namespace ", "nullptr"}},
+	{"rust", []string{"fn ", "let mut ", "impl ", "pub fn", "println!", "match ", "::new("}},
+	{"ruby", []string{"def ", "end", "puts ", "require '", "do |", "attr_accessor"}},
+	{"php", []string{"<?php", "$this->", "function ", "echo ", "=> ", "namespace "}},
+}
+
+// Text 对文本内容分类，返回细分类型；源代码同时返回语言猜测
+func Text(text string) (model.TextSubtype, string) {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return model.SubtypePlain, ""
+	}
+	singleLine := !strings.ContainsAny(trimmed, "\r\n")
+
+	if singleLine {
+		switch {
+		case hexColorRe.MatchString(trimmed), rgbColorRe.MatchString(trimmed):
+			return model.SubtypeColor, ""
+		case isURL(trimmed):
+			return model.SubtypeURL, ""
+		case emailRe.MatchString(trimmed):
+			return model.SubtypeEmail, ""
+		case isPhone(trimmed):
+			return model.SubtypePhone, ""
+		case isPath(trimmed):
+			return model.SubtypePath, ""
+		}
+	}
+
+	if (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && json.Valid([]byte(trimmed)) {
+		return model.SubtypeJSON, ""
+	}
+	if xmlRe.MatchString(trimmed) && (strings.Contains(trimmed, "</") || strings.Contains(trimmed, "/>")) {
+		return model.SubtypeXML, ""
+	}
+	if sqlRe.MatchString(trimmed) {
+		return model.SubtypeSQL, ""
+	}
+	if isShell(trimmed) {
+		return model.SubtypeShell, ""
+	}
+	if language := guessLanguage(trimmed); language != "" {
+		return model.SubtypeCode, language
+	}
+
+	return model.SubtypePlain, ""
+}
+
+// isURL 判断是否为网址
+func isURL(text string) bool {
+	if strings.ContainsAny(text, " \t") {
+		return false
+	}
+	if strings.HasPrefix(text, "www.") {
+		text = "http://" + text
+	}
+	u, err := url.Parse(text)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	switch u.Scheme {
+	case "http", "https", "ftp", "ftps", "ws", "wss":
+		return true
+	}
+	return false
+}
+
+// isPhone 判断是否为电话号码（至少 7 位数字）
+func isPhone(text string) bool {
+	if !phoneRe.MatchString(text) || dateRe.MatchString(text) {
+		return false
+	}
+	digits := 0
+	for _, r := range text {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	return digits >= 7 && digits <= 15
+}
+
+// isPath 判断是否为文件路径（不要求文件存在）
+func isPath(text string) bool {
+	switch {
+	case winPathRe.MatchString(text):
+		return true
+	case strings.HasPrefix(text, "~/"), strings.HasPrefix(text, "./"), strings.HasPrefix(text, "../"):
+		return true
+	case strings.HasPrefix(text, "/") && len(text) > 1 && !strings.Contains(text, "//"):
+		return strings.Count(text, "/") >= 2 || !strings.ContainsAny(text, " ")
+	}
+	return false
+}
+
+// isShell 判断是否为 Shell 命令
+func isShell(text string) bool {
+	firstLine := strings.SplitN(text, "\n", 2)[0]
+	firstLine = strings.TrimPrefix(strings.TrimSpace(firstLine), "$ ")
+	fields := strings.Fields(firstLine)
+	if len(fields) == 0 {
+		return false
+	}
+	if !shellCommands[fields[0]] {
+		return strings.HasPrefix(text, "#!/") && strings.Contains(firstLine, "sh")
+	}
+	// 单个命令词不足以判断，如 "go"、"make"
+	return len(fields) > 1
+}
+
+// guessLanguage 根据特征词猜测源代码语言，无法判断时返回空
+func guessLanguage(text string) string {
+	// 源代码通常多行，或单行中带有语句结束符
+	if !strings.Contains(text, "\n") && !strings.ContainsAny(text, ";{}") {
+		return ""
+	}
+
+	best, bestScore := "", 1
+	for _, lang := range languageHints {
+		score := 0
+		for _, hint := range lang.hints {
+			if strings.Contains(text, hint) {
+				score++
+			}
+		}
+		if score > bestScore {
+			best, bestScore = lang.language, score
+		}
+	}
+	return best
+}
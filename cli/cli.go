@@ -0,0 +1,188 @@
+package cli
+
+import (
+	"clipboard/config"
+	"clipboard/instance"
+	"clipboard/ipc"
+	"clipboard/storage"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+)
+
+// command 子命令定义
+type command struct {
+	name  string
+	usage string // 参数说明
+	help  string // 简要说明
+	run   func(c *context, args []string) error
+}
+
+// commands 所有子命令，按帮助中的显示顺序排列
+var commands = []command{
+	{"list", "[-n 数量] [--type 类型] [--fav]", "列出历史记录", runList},
+	{"search", "<关键词>", "搜索历史记录（支持 type:、lang:、format:、width>1920 等过滤）", runSearch},
+	{"show", "<id> [--reveal]", "显示项的完整内容", runShow},
+	{"copy", "<id>", "将项写入系统剪贴板", runCopy},
+	{"add", "[--tag 标签] [--fav]", "从标准输入读取文本并添加为新项", runAdd},
+	{"delete", "<id>...", "删除项", runDelete},
+	{"fav", "<id>...", "切换收藏状态", runFav},
+	{"export", "[-o 文件]", "导出历史记录为 JSON（不含敏感内容）", runExport},
+	{"import", "[文件]", "从 JSON 导入历史记录（默认读取标准输入）", runImport},
+	{"stats", "", "显示使用统计", runStats},
+	{"gc", "[--dry-run]", "清理过期项和无主的图片、缩略图、快照文件", runGC},
+}
+
+// captureWait 捕获锁被占用时等待对方开始监听的时间
+const captureWait = 3 * time.Second
+
+// ErrUsage 参数错误
+var ErrUsage = errors.New("参数错误")
+
+// context 子命令的执行环境
+type context struct {
+	store  storage.Storage
+	client *ipc.Client // 连接到后台服务时非空
+	config *config.AppConfig
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	json   bool // 以 JSON 输出
+}
+
+// IsCommand 判断参数是否为命令行子命令（用于区分图形界面启动）
+func IsCommand(name string) bool {
+	if name == "help" || name == "-h" || name == "--help" {
+		return true
+	}
+	_, ok := lookup(name)
+	return ok
+}
+
+// Run 执行命令行子命令，返回进程退出码
+func Run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(stdout)
+		return 0
+	}
+
+	cmd, ok := lookup(args[0])
+	if !ok {
+		fmt.Fprintf(stderr, "未知命令: %s\n\n", args[0])
+		printUsage(stderr)
+		return 2
+	}
+
+	// 命令行模式下默认不输出调试日志
+	if os.Getenv("CLIPBOARD_DEBUG") == "" {
+		log.SetOutput(io.Discard)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(stderr, "加载配置失败: %v\n", err)
+		return 1
+	}
+	c := &context{config: cfg, stdin: stdin, stdout: stdout, stderr: stderr}
+
+	// 后台服务或本地捕获的图形界面在运行时通过它读写，避免两个进程同时写入存储
+	client, err := ipc.Dial(ipc.SocketPath())
+	if err != nil {
+		// 没有可连接的进程时持有捕获锁直接读写；锁被占用说明对方刚启动、尚未开始监听
+		lock, lockErr := instance.LockCapture()
+		switch {
+		case errors.Is(lockErr, instance.ErrRunning):
+			if client, err = ipc.DialWait(ipc.SocketPath(), captureWait); err != nil {
+				fmt.Fprintf(stderr, "其他进程正在捕获剪贴板且无法连接: %v\n", err)
+				return 1
+			}
+		case lockErr != nil:
+			log.Printf("获取捕获锁失败，继续直接读写存储: %v", lockErr)
+		default:
+			defer lock.Release()
+		}
+	}
+	if client != nil {
+		c.client = client
+		c.store = ipc.NewRemoteStorage(client)
+	} else if c.store, err = storage.NewStorage(&cfg.Storage); err != nil {
+		fmt.Fprintf(stderr, "打开存储失败: %v\n", err)
+		return 1
+	}
+	defer c.store.Close()
+
+	if err := cmd.run(c, args[1:]); err != nil {
+		if errors.Is(err, ErrUsage) {
+			fmt.Fprintf(stderr, "%v\n用法: clipboard %s %s [--json]\n", err, cmd.name, cmd.usage)
+			return 2
+		}
+		fmt.Fprintf(stderr, "%s: %v\n", cmd.name, err)
+		return 1
+	}
+	return 0
+}
+
+// lookup 查找子命令
+func lookup(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// printUsage 打印帮助信息
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "用法: clipboard [命令] [参数] [--json]")
+	fmt.Fprintln(w, "不带命令时启动图形界面；clipboard daemon 在后台运行捕获服务，")
+	fmt.Fprintln(w, "此时图形界面与命令行都通过它读写历史记录；没有后台服务时命令行通过运行中的图形界面读写。")
+	fmt.Fprintln(w, "图形界面只运行一个实例，再次启动时转发给已运行的实例：")
+	fmt.Fprintln(w, "  clipboard --search <搜索词>   显示窗口并搜索")
+	fmt.Fprintln(w, "  clipboard --paste <id>        将指定项写入剪贴板")
+	fmt.Fprintln(w, "  clipboard --pick              打开快速粘贴窗口")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "命令:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-8s %-28s %s\n", cmd.name, cmd.usage, cmd.help)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "id 可以使用 list 输出中的前缀。设置 CLIPBOARD_DEBUG=1 可输出调试日志。")
+}
+
+// newFlags 创建带 --json 选项的参数解析器
+func (c *context) newFlags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.BoolVar(&c.json, "json", false, "以 JSON 输出")
+	return fs
+}
+
+// parse 解析参数，允许选项出现在位置参数之后，返回位置参数
+func parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUsage, err)
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// writeJSON 以缩进格式输出 JSON
+func (c *context) writeJSON(v any) error {
+	enc := json.NewEncoder(c.stdout)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
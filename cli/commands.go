@@ -0,0 +1,598 @@
+package cli
+
+import (
+	"clipboard/classify"
+	"clipboard/clipboard"
+	"clipboard/filelist"
+	"clipboard/ipc"
+	"clipboard/model"
+	"clipboard/sensitive"
+	"clipboard/stats"
+	"clipboard/thumbnail"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	iofs "io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// runList 列出历史记录
+func runList(c *context, args []string) error {
+	fs := c.newFlags("list")
+	limit := fs.Int("n", 20, "最多显示的数量，0 表示全部")
+	typeName := fs.String("type", "", "只显示指定类型（text/image/file/snippet 或文本细分类型）")
+	favorites := fs.Bool("fav", false, "只显示收藏项")
+	if _, err := parse(fs, args); err != nil {
+		return err
+	}
+
+	query := ""
+	if *typeName != "" {
+		query = "type:" + *typeName
+	}
+	items, err := c.store.Search(query)
+	if err != nil {
+		return err
+	}
+
+	var filtered []*model.ClipboardItem
+	for _, item := range items {
+		if *favorites && !item.IsFavorite {
+			continue
+		}
+		filtered = append(filtered, item)
+		if *limit > 0 && len(filtered) >= *limit {
+			break
+		}
+	}
+	return c.printItems(filtered)
+}
+
+// runSearch 搜索历史记录
+func runSearch(c *context, args []string) error {
+	fs := c.newFlags("search")
+	words, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(words) == 0 {
+		return fmt.Errorf("%w: 缺少关键词", ErrUsage)
+	}
+
+	items, err := c.store.Search(strings.Join(words, " "))
+	if err != nil {
+		return err
+	}
+	return c.printItems(items)
+}
+
+// runShow 显示项的完整内容
+func runShow(c *context, args []string) error {
+	fs := c.newFlags("show")
+	reveal := fs.Bool("reveal", false, "显示敏感内容")
+	ids, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(ids) != 1 {
+		return fmt.Errorf("%w: 需要一个 id", ErrUsage)
+	}
+
+	item, err := c.find(ids[0])
+	if err != nil {
+		return err
+	}
+	if !*reveal {
+		item = item.Redacted()
+	}
+	if c.json {
+		return c.writeJSON(item)
+	}
+
+	fmt.Fprintf(c.stdout, "ID:     %s\n", item.ID)
+	fmt.Fprintf(c.stdout, "类型:   %s\n", typeLabel(item))
+	fmt.Fprintf(c.stdout, "时间:   %s\n", item.Timestamp.Format("2006-01-02 15:04:05"))
+	if item.IsFavorite {
+		fmt.Fprintln(c.stdout, "收藏:   是")
+	}
+	if len(item.Tags) > 0 {
+		fmt.Fprintf(c.stdout, "标签:   %s\n", strings.Join(item.Tags, ", "))
+	}
+	if item.PasteCount > 0 {
+		fmt.Fprintf(c.stdout, "粘贴:   %d 次\n", item.PasteCount)
+	}
+	if item.ExpiresAt != nil {
+		fmt.Fprintf(c.stdout, "过期:   %s\n", item.ExpiresAt.Format("2006-01-02 15:04:05"))
+	}
+
+	switch item.Type {
+	case model.TypeImage:
+		fmt.Fprintf(c.stdout, "文件:   %s\n", item.ImagePath)
+		if item.Width > 0 {
+			fmt.Fprintf(c.stdout, "尺寸:   %d×%d %s %s\n", item.Width, item.Height,
+				strings.ToUpper(item.Format), stats.FormatBytes(item.ByteSize))
+		}
+	case model.TypeFile:
+		fmt.Fprintln(c.stdout)
+		for _, entry := range filelist.Entries(item) {
+			state := ""
+			if !filelist.Exists(entry) {
+				state = "（已丢失）"
+				if entry.Snapshot != "" {
+					state = "（已丢失，快照: " + entry.Snapshot + "）"
+				}
+			}
+			fmt.Fprintf(c.stdout, "%s%s\n", entry.Path, state)
+		}
+	default:
+		fmt.Fprintln(c.stdout)
+		fmt.Fprintln(c.stdout, item.Content)
+	}
+	return nil
+}
+
+// runCopy 将项写入系统剪贴板
+func runCopy(c *context, args []string) error {
+	fs := c.newFlags("copy")
+	ids, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(ids) != 1 {
+		return fmt.Errorf("%w: 需要一个 id", ErrUsage)
+	}
+
+	item, err := c.find(ids[0])
+	if err != nil {
+		return err
+	}
+	// 后台服务在运行时由它持有剪贴板内容并记录使用次数
+	if c.client != nil {
+		if err := c.client.Call(ipc.MethodSetClipboard, ipc.SetClipboardParams{ID: item.ID}, nil); err != nil {
+			return err
+		}
+		return c.printCopied(item)
+	}
+
+	done, err := clipboard.WriteDetached(item)
+	if err != nil {
+		return err
+	}
+	if err := c.store.RecordUse(item.ID); err != nil {
+		fmt.Fprintf(c.stderr, "记录使用次数失败: %v\n", err)
+	}
+	if err := c.printCopied(item); err != nil {
+		return err
+	}
+
+	// 没有 xclip 时内容由本进程持有，等待被其他程序覆盖后再退出
+	select {
+	case <-done:
+	default:
+		fmt.Fprintln(c.stderr, "剪贴板内容由本进程持有，被其他内容覆盖后退出（或按 Ctrl+C）")
+		<-done
+	}
+	return nil
+}
+
+// printCopied 输出复制结果
+func (c *context) printCopied(item *model.ClipboardItem) error {
+	if c.json {
+		return c.writeJSON(map[string]string{"copied": item.ID})
+	}
+	fmt.Fprintf(c.stdout, "已复制 %s\n", shortID(item.ID))
+	return nil
+}
+
+// runAdd 从标准输入读取文本并添加为新项
+func runAdd(c *context, args []string) error {
+	fs := c.newFlags("add")
+	tag := fs.String("tag", "", "添加标签")
+	favorite := fs.Bool("fav", false, "添加为收藏")
+	if _, err := parse(fs, args); err != nil {
+		return err
+	}
+
+	data, err := io.ReadAll(c.stdin)
+	if err != nil {
+		return fmt.Errorf("读取标准输入失败: %w", err)
+	}
+	text := strings.TrimSuffix(string(data), "\n")
+	if strings.TrimSpace(text) == "" {
+		return errors.New("标准输入为空")
+	}
+
+	item := model.NewClipboardItem(model.TypeText, text, "")
+	item.Subtype, item.Language = classify.Text(text)
+	item.IsFavorite = *favorite
+	if *tag != "" {
+		item.AddTag(*tag)
+	}
+
+	// 与监听器一致：敏感内容按配置自动过期
+	sensitive.Mark(item, c.config.Sensitive)
+
+	if _, err := c.store.AddItem(item); err != nil {
+		return err
+	}
+	if c.json {
+		return c.writeJSON(item.Redacted())
+	}
+	fmt.Fprintf(c.stdout, "已添加 %s\n", shortID(item.ID))
+	return nil
+}
+
+// runDelete 删除项
+func runDelete(c *context, args []string) error {
+	fs := c.newFlags("delete")
+	ids, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("%w: 需要至少一个 id", ErrUsage)
+	}
+
+	var deleted []string
+	for _, id := range ids {
+		item, err := c.find(id)
+		if err != nil {
+			return err
+		}
+		if _, err := c.store.DeleteItem(item.ID); err != nil {
+			return fmt.Errorf("删除 %s 失败: %w", shortID(item.ID), err)
+		}
+		deleted = append(deleted, item.ID)
+	}
+
+	if c.json {
+		return c.writeJSON(map[string][]string{"deleted": deleted})
+	}
+	fmt.Fprintf(c.stdout, "已删除 %d 项\n", len(deleted))
+	return nil
+}
+
+// runFav 切换收藏状态
+func runFav(c *context, args []string) error {
+	fs := c.newFlags("fav")
+	ids, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("%w: 需要至少一个 id", ErrUsage)
+	}
+
+	result := make(map[string]bool)
+	for _, id := range ids {
+		item, err := c.find(id)
+		if err != nil {
+			return err
+		}
+		if _, err := c.store.ToggleFavorite(item.ID); err != nil {
+			return fmt.Errorf("切换 %s 收藏状态失败: %w", shortID(item.ID), err)
+		}
+		result[item.ID] = !item.IsFavorite
+		if !c.json {
+			state := "已收藏"
+			if item.IsFavorite {
+				state = "已取消收藏"
+			}
+			fmt.Fprintf(c.stdout, "%s %s\n", shortID(item.ID), state)
+		}
+	}
+
+	if c.json {
+		return c.writeJSON(result)
+	}
+	return nil
+}
+
+// runExport 导出历史记录（敏感内容不导出）
+func runExport(c *context, args []string) error {
+	fs := c.newFlags("export")
+	output := fs.String("o", "", "输出文件，默认写到标准输出")
+	if _, err := parse(fs, args); err != nil {
+		return err
+	}
+
+	items, err := c.store.LoadItems()
+	if err != nil {
+		return err
+	}
+	exported := make([]*model.ClipboardItem, 0, len(items))
+	for _, item := range items {
+		if item.Exportable() {
+			exported = append(exported, item)
+		}
+	}
+
+	data, err := json.MarshalIndent(exported, "", "  ")
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = c.stdout.Write(append(data, '\n'))
+		return err
+	}
+	if err := os.WriteFile(*output, data, 0644); err != nil {
+		return fmt.Errorf("写入导出文件失败: %w", err)
+	}
+	fmt.Fprintf(c.stderr, "已导出 %d 项到 %s（跳过 %d 个敏感项）\n", len(exported), *output, len(items)-len(exported))
+	return nil
+}
+
+// runImport 从 JSON 导入历史记录，内容重复的项被跳过
+func runImport(c *context, args []string) error {
+	fs := c.newFlags("import")
+	files, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(files) > 1 {
+		return fmt.Errorf("%w: 最多一个输入文件", ErrUsage)
+	}
+
+	var data []byte
+	if len(files) == 0 || files[0] == "-" {
+		data, err = io.ReadAll(c.stdin)
+	} else {
+		data, err = os.ReadFile(files[0])
+	}
+	if err != nil {
+		return fmt.Errorf("读取导入数据失败: %w", err)
+	}
+
+	var items []*model.ClipboardItem
+	if err := json.Unmarshal(data, &items); err != nil {
+		return fmt.Errorf("解析导入数据失败: %w", err)
+	}
+
+	existing, err := c.store.LoadItems()
+	if err != nil {
+		return err
+	}
+	ids := make(map[string]bool, len(existing))
+	for _, item := range existing {
+		ids[item.ID] = true
+	}
+
+	imported := 0
+	for _, item := range items {
+		if item == nil || (item.Content == "" && item.ImagePath == "") {
+			continue
+		}
+		// ID 冲突时重新生成，避免覆盖已有项
+		if item.ID == "" || ids[item.ID] {
+			item.ID = uuid.NewString()
+		}
+		if item.Timestamp.IsZero() {
+			item.Timestamp = time.Now()
+		}
+		item.Revisions = nil
+		ids[item.ID] = true
+
+		current, err := c.store.AddItem(item)
+		if err != nil {
+			return fmt.Errorf("导入失败: %w", err)
+		}
+		// 达到数量上限时添加一项会移除最旧的项，总数不变，因此按返回列表中是否有该 ID 计数
+		if slices.ContainsFunc(current, func(it *model.ClipboardItem) bool { return it.ID == item.ID }) {
+			imported++
+		}
+	}
+
+	if c.json {
+		return c.writeJSON(map[string]int{"total": len(items), "imported": imported})
+	}
+	fmt.Fprintf(c.stdout, "已导入 %d 项（共 %d 项，其余重复或超出数量上限）\n", imported, len(items))
+	return nil
+}
+
+// runStats 显示使用统计
+func runStats(c *context, args []string) error {
+	fs := c.newFlags("stats")
+	if _, err := parse(fs, args); err != nil {
+		return err
+	}
+
+	report, err := stats.NewService(c.store).Compute()
+	if err != nil {
+		return err
+	}
+	if c.json {
+		return c.writeJSON(report)
+	}
+
+	fmt.Fprintf(c.stdout, "共 %d 项，收藏 %d 项，累计粘贴 %d 次\n", report.TotalItems, report.Favorites, report.TotalPastes)
+	fmt.Fprintf(c.stdout, "存储占用 %s（数据 %s，图片 %d 个 %s，快照 %s）\n",
+		stats.FormatBytes(report.Storage.TotalBytes), stats.FormatBytes(report.Storage.DataBytes),
+		report.Storage.ImageFiles, stats.FormatBytes(report.Storage.ImageBytes),
+		stats.FormatBytes(report.Storage.SnapshotBytes))
+
+	fmt.Fprintln(c.stdout, "\n类型分布:")
+	types := make([]string, 0, len(report.Types))
+	for key := range report.Types {
+		types = append(types, key)
+	}
+	sort.Strings(types)
+	for _, key := range types {
+		fmt.Fprintf(c.stdout, "  %-16s %d\n", key, report.Types[key])
+	}
+	if len(report.TopDomains) > 0 {
+		fmt.Fprintln(c.stdout, "\n常用域名:")
+		for _, d := range report.TopDomains {
+			fmt.Fprintf(c.stdout, "  %-32s %d\n", d.Domain, d.Count)
+		}
+	}
+	if len(report.MostPasted) > 0 {
+		fmt.Fprintln(c.stdout, "\n最常粘贴:")
+		for _, p := range report.MostPasted {
+			fmt.Fprintf(c.stdout, "  %-8s %4d 次  %s\n", shortID(p.ID), p.PasteCount, p.Preview)
+		}
+	}
+	return nil
+}
+
+// runGC 清理过期项和不再被引用的本地文件
+func runGC(c *context, args []string) error {
+	fs := c.newFlags("gc")
+	dryRun := fs.Bool("dry-run", false, "只列出将被清理的内容")
+	if _, err := parse(fs, args); err != nil {
+		return err
+	}
+
+	expired := 0
+	if !*dryRun {
+		n, err := c.store.PurgeExpired()
+		if err != nil {
+			return fmt.Errorf("清理过期项失败: %w", err)
+		}
+		expired = n
+	}
+
+	items, err := c.store.LoadItems()
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		now := time.Now()
+		for _, item := range items {
+			if item.IsExpired(now) {
+				expired++
+			}
+		}
+	}
+	referenced := make(map[string]bool)
+	for _, item := range items {
+		if item.ImagePath != "" {
+			referenced[item.ImagePath] = true
+			referenced[thumbnail.Path(item.ImagePath)] = true
+		}
+		for _, entry := range item.Files {
+			if entry.Snapshot != "" {
+				referenced[entry.Snapshot] = true
+			}
+		}
+	}
+
+	imageDir := c.store.GetImagePath()
+	snapshotDir := filelist.SnapshotDir(imageDir)
+	var orphans []string
+	var freed int64
+	for _, dir := range []string{imageDir, snapshotDir} {
+		filepath.WalkDir(dir, func(path string, d iofs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || referenced[path] {
+				return nil
+			}
+			if info, err := d.Info(); err == nil {
+				freed += info.Size()
+			}
+			orphans = append(orphans, path)
+			return nil
+		})
+	}
+
+	if !*dryRun {
+		for _, path := range orphans {
+			os.Remove(path)
+			// 单个项的快照目录清空后一并删除，图片目录与快照根目录本身保留
+			if dir := filepath.Dir(path); filepath.Dir(dir) == snapshotDir {
+				os.Remove(dir)
+			}
+		}
+	}
+
+	if c.json {
+		return c.writeJSON(map[string]any{
+			"dryRun":       *dryRun,
+			"expiredItems": expired,
+			"orphanFiles":  orphans,
+			"freedBytes":   freed,
+		})
+	}
+	for _, path := range orphans {
+		if *dryRun {
+			fmt.Fprintln(c.stdout, path)
+		}
+	}
+	verb := "已清理"
+	if *dryRun {
+		verb = "可清理"
+	}
+	fmt.Fprintf(c.stdout, "%s %d 个过期项，%d 个无主文件（%s）\n", verb, expired, len(orphans), stats.FormatBytes(freed))
+	return nil
+}
+
+// printItems 输出项列表
+func (c *context) printItems(items []*model.ClipboardItem) error {
+	if c.json {
+		redacted := make([]*model.ClipboardItem, 0, len(items))
+		for _, item := range items {
+			redacted = append(redacted, item.Redacted())
+		}
+		return c.writeJSON(redacted)
+	}
+
+	for _, item := range items {
+		mark := " "
+		if item.IsFavorite {
+			mark = "★"
+		}
+		fmt.Fprintf(c.stdout, "%-8s %s %s %-12s %s\n", shortID(item.ID), mark,
+			item.Timestamp.Format("01-02 15:04"), typeLabel(item), preview(item))
+	}
+	return nil
+}
+
+// find 按 ID 或唯一的 ID 前缀查找项
+func (c *context) find(id string) (*model.ClipboardItem, error) {
+	items, err := c.store.LoadItems()
+	if err != nil {
+		return nil, err
+	}
+	return model.FindByIDPrefix(items, id)
+}
+
+// typeLabel 类型名称，文本附带细分类型
+func typeLabel(item *model.ClipboardItem) string {
+	if item.Type == model.TypeText && item.Subtype != "" && item.Subtype != model.SubtypePlain {
+		return item.Type.String() + "/" + string(item.Subtype)
+	}
+	return item.Type.String()
+}
+
+// preview 单行内容预览
+func preview(item *model.ClipboardItem) string {
+	switch {
+	case item.Sensitive:
+		return "[敏感内容] " + model.MaskedContent
+	case item.Type == model.TypeImage:
+		if item.Width > 0 {
+			return fmt.Sprintf("%d×%d %s", item.Width, item.Height, filepath.Base(item.ImagePath))
+		}
+		return filepath.Base(item.ImagePath)
+	}
+
+	text := []rune(strings.Join(strings.Fields(item.Content), " "))
+	if len(text) > 60 {
+		return string(text[:60]) + "..."
+	}
+	return string(text)
+}
+
+// shortID 列表中显示的短 ID
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
@@ -0,0 +1,75 @@
+package clipboard
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrConsumeUnsupported 当前环境无法检测其他程序的粘贴
+var ErrConsumeUnsupported = errors.New("当前环境不支持检测粘贴")
+
+// consumeGrace 取得选区后的这段时间内的读取不视为粘贴：
+// 剪贴板管理器会在内容变化后立即读取，同一次粘贴也可能按多种格式读取
+const consumeGrace = 300 * time.Millisecond
+
+// consumeWatcher 持有 CLIPBOARD 选区并在其他程序读取内容时发出通知，用于粘贴队列自动前进
+type consumeWatcher struct {
+	offers    chan consumeOffer
+	consumed  chan struct{}
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// consumeOffer 提供给其他程序读取的内容
+type consumeOffer struct {
+	data   []byte
+	image  bool      // 为 true 时以 image/png 提供，否则以文本提供
+	result chan bool // 是否成功取得选区
+}
+
+// newConsumeWatcher 创建未启动的选区持有者，由平台实现的 startConsumeWatcher 启动
+func newConsumeWatcher() *consumeWatcher {
+	return &consumeWatcher{
+		offers:   make(chan consumeOffer),
+		consumed: make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Offer 取得选区并提供 data，内容过大或无法取得选区时返回 false
+func (w *consumeWatcher) Offer(data []byte, image bool) bool {
+	offer := consumeOffer{data: data, image: image, result: make(chan bool, 1)}
+	select {
+	case w.offers <- offer:
+		return <-offer.result
+	case <-w.done:
+		return false
+	}
+}
+
+// Consumed 其他程序读取了当前内容时收到通知，每次提供的内容最多通知一次
+func (w *consumeWatcher) Consumed() <-chan struct{} {
+	return w.consumed
+}
+
+// Close 停止持有选区，仍持有时内容交回剪贴板库继续提供，可重复调用
+func (w *consumeWatcher) Close() {
+	if w == nil {
+		return
+	}
+	w.closeOnce.Do(func() {
+		close(w.stop)
+	})
+	<-w.done
+}
+
+// notify 发出读取通知，上一次还未处理时丢弃
+func (w *consumeWatcher) notify() {
+	select {
+	case w.consumed <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,334 @@
+//go:build linux && cgo
+
+package clipboard
+
+/*
+#cgo LDFLAGS: -ldl
+
+#include <dlfcn.h>
+#include <errno.h>
+#include <poll.h>
+#include <stdlib.h>
+#include <string.h>
+#include <X11/Xlib.h>
+#include <X11/Xatom.h>
+
+// 与 golang.design/x/clipboard 一样在运行时加载 libX11，没有 X11 时不影响启动
+static void *libX11;
+static Display *(*pXOpenDisplay)(const char *);
+static int (*pXCloseDisplay)(Display *);
+static Window (*pXDefaultRootWindow)(Display *);
+static Window (*pXCreateSimpleWindow)(Display *, Window, int, int, unsigned int, unsigned int, unsigned int, unsigned long, unsigned long);
+static Atom (*pXInternAtom)(Display *, const char *, Bool);
+static int (*pXSetSelectionOwner)(Display *, Atom, Window, Time);
+static Window (*pXGetSelectionOwner)(Display *, Atom);
+static int (*pXChangeProperty)(Display *, Window, Atom, Atom, int, int, const unsigned char *, int);
+static Status (*pXSendEvent)(Display *, Window, Bool, long, XEvent *);
+static int (*pXPending)(Display *);
+static int (*pXNextEvent)(Display *, XEvent *);
+static int (*pXConnectionNumber)(Display *);
+static int (*pXFlush)(Display *);
+static long (*pXMaxRequestSize)(Display *);
+static long (*pXExtendedMaxRequestSize)(Display *);
+static XErrorHandler (*pXSetErrorHandler)(XErrorHandler);
+
+static int loadX11(void) {
+	if (libX11) {
+		return 1;
+	}
+	libX11 = dlopen("libX11.so.6", RTLD_LAZY);
+	if (!libX11) {
+		libX11 = dlopen("libX11.so", RTLD_LAZY);
+	}
+	if (!libX11) {
+		return 0;
+	}
+	pXOpenDisplay = dlsym(libX11, "XOpenDisplay");
+	pXCloseDisplay = dlsym(libX11, "XCloseDisplay");
+	pXDefaultRootWindow = dlsym(libX11, "XDefaultRootWindow");
+	pXCreateSimpleWindow = dlsym(libX11, "XCreateSimpleWindow");
+	pXInternAtom = dlsym(libX11, "XInternAtom");
+	pXSetSelectionOwner = dlsym(libX11, "XSetSelectionOwner");
+	pXGetSelectionOwner = dlsym(libX11, "XGetSelectionOwner");
+	pXChangeProperty = dlsym(libX11, "XChangeProperty");
+	pXSendEvent = dlsym(libX11, "XSendEvent");
+	pXPending = dlsym(libX11, "XPending");
+	pXNextEvent = dlsym(libX11, "XNextEvent");
+	pXConnectionNumber = dlsym(libX11, "XConnectionNumber");
+	pXFlush = dlsym(libX11, "XFlush");
+	pXMaxRequestSize = dlsym(libX11, "XMaxRequestSize");
+	pXExtendedMaxRequestSize = dlsym(libX11, "XExtendedMaxRequestSize");
+	pXSetErrorHandler = dlsym(libX11, "XSetErrorHandler");
+	return 1;
+}
+
+typedef struct {
+	Display *d;
+	Window w;
+	Atom clipboard, targets, utf8, string, text, textPlain, textPlainUTF8, png;
+	Atom readerProp; // golang.design/x/clipboard 读取时使用的属性，用于识别本程序自己的读取
+	unsigned char *data;
+	int len;
+	int image;
+} owner;
+
+static Display *ownerDisplay;
+static XErrorHandler prevHandler;
+
+// 请求方窗口在应答前关闭等错误不应让进程退出，其他连接的错误交给原处理函数
+static int onOwnerError(Display *d, XErrorEvent *e) {
+	if (d == ownerDisplay) {
+		return 0;
+	}
+	return prevHandler ? prevHandler(d, e) : 0;
+}
+
+static owner *openOwner(void) {
+	if (!loadX11()) {
+		return NULL;
+	}
+	Display *d = pXOpenDisplay(NULL);
+	if (!d) {
+		return NULL;
+	}
+
+	owner *o = calloc(1, sizeof(owner));
+	o->d = d;
+	o->w = pXCreateSimpleWindow(d, pXDefaultRootWindow(d), 0, 0, 1, 1, 0, 0, 0);
+	o->clipboard = pXInternAtom(d, "CLIPBOARD", False);
+	o->targets = pXInternAtom(d, "TARGETS", False);
+	o->utf8 = pXInternAtom(d, "UTF8_STRING", False);
+	o->string = XA_STRING;
+	o->text = pXInternAtom(d, "TEXT", False);
+	o->textPlain = pXInternAtom(d, "text/plain", False);
+	o->textPlainUTF8 = pXInternAtom(d, "text/plain;charset=utf-8", False);
+	o->png = pXInternAtom(d, "image/png", False);
+	o->readerProp = pXInternAtom(d, "GOLANG_DESIGN_DATA", False);
+
+	ownerDisplay = d;
+	prevHandler = pXSetErrorHandler(onOwnerError);
+	return o;
+}
+
+static void closeOwner(owner *o) {
+	pXCloseDisplay(o->d);
+	free(o->data);
+	free(o);
+}
+
+// maxData 单次 XChangeProperty 可发送的最大字节数，更大的内容需要 INCR 协议，这里不支持
+static long maxData(owner *o) {
+	long n = pXExtendedMaxRequestSize(o->d);
+	if (n == 0) {
+		n = pXMaxRequestSize(o->d);
+	}
+	return n * 4 - 1024;
+}
+
+// own 持有 CLIPBOARD 选区并提供 data，成功返回 1
+static int own(owner *o, const void *data, int len, int image) {
+	if (len > maxData(o)) {
+		return 0;
+	}
+	free(o->data);
+	o->data = malloc(len > 0 ? len : 1);
+	memcpy(o->data, data, len);
+	o->len = len;
+	o->image = image;
+
+	pXSetSelectionOwner(o->d, o->clipboard, o->w, CurrentTime);
+	if (pXGetSelectionOwner(o->d, o->clipboard) != o->w) {
+		free(o->data);
+		o->data = NULL;
+		return 0;
+	}
+	return 1;
+}
+
+static int isTextTarget(owner *o, Atom target) {
+	return target == o->utf8 || target == o->string || target == o->text ||
+		target == o->textPlain || target == o->textPlainUTF8;
+}
+
+// answer 应答读取请求，其他程序读取了内容时返回 1
+static int answer(owner *o, XSelectionRequestEvent *r) {
+	XSelectionEvent ev = {0};
+	ev.type = SelectionNotify;
+	ev.display = r->display;
+	ev.requestor = r->requestor;
+	ev.selection = r->selection;
+	ev.time = r->time;
+	ev.target = r->target;
+	// 旧协议的请求方不指定属性，此时使用目标名
+	ev.property = r->property != None ? r->property : r->target;
+
+	int consumed = 0;
+	if (r->selection != o->clipboard || !o->data) {
+		ev.property = None;
+	} else if (r->target == o->targets) {
+		Atom targets[6];
+		int n = 0;
+		targets[n++] = o->targets;
+		if (o->image) {
+			targets[n++] = o->png;
+		} else {
+			targets[n++] = o->utf8;
+			targets[n++] = o->textPlainUTF8;
+			targets[n++] = o->string;
+			targets[n++] = o->text;
+		}
+		pXChangeProperty(o->d, r->requestor, ev.property, XA_ATOM, 32, PropModeReplace, (unsigned char *)targets, n);
+	} else if (o->image ? r->target == o->png : isTextTarget(o, r->target)) {
+		Atom type = r->target == o->text ? o->utf8 : r->target;
+		pXChangeProperty(o->d, r->requestor, ev.property, type, 8, PropModeReplace, o->data, o->len);
+		consumed = r->property != o->readerProp;
+	} else {
+		ev.property = None;
+	}
+	pXSendEvent(o->d, r->requestor, False, 0, (XEvent *)&ev);
+	pXFlush(o->d);
+	return consumed;
+}
+
+// waitEvent 处理选区事件：内容被其他程序读取返回 1，失去选区返回 2，
+// 超时或其他事件返回 0，连接断开返回 -1
+static int waitEvent(owner *o, int timeoutMs) {
+	int result = 0;
+	while (pXPending(o->d) > 0) {
+		XEvent ev;
+		pXNextEvent(o->d, &ev);
+		switch (ev.type) {
+		case SelectionRequest:
+			if (answer(o, &ev.xselectionrequest)) {
+				result = 1;
+			}
+			break;
+		case SelectionClear:
+			if (ev.xselectionclear.selection == o->clipboard) {
+				free(o->data);
+				o->data = NULL;
+				return 2;
+			}
+			break;
+		}
+	}
+	if (result) {
+		return result;
+	}
+
+	struct pollfd pfd = {pXConnectionNumber(o->d), POLLIN, 0};
+	int n = poll(&pfd, 1, timeoutMs);
+	if (n < 0) {
+		return errno == EINTR ? 0 : -1;
+	}
+	if (n > 0 && (pfd.revents & (POLLERR | POLLHUP))) {
+		return -1;
+	}
+	return 0;
+}
+
+// owns 是否仍持有选区
+static int owns(owner *o) {
+	return o->data != NULL;
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"golang.design/x/clipboard"
+	"log"
+	"runtime"
+	"time"
+	"unsafe"
+)
+
+// 等待选区事件的超时，决定提供新内容与停止的响应时间
+const consumePollTimeout = 100
+
+// startConsumeWatcher 在独立的 X11 连接上运行选区持有者
+func startConsumeWatcher() (*consumeWatcher, error) {
+	w := newConsumeWatcher()
+	errc := make(chan error, 1)
+	go w.run(errc)
+	if err := <-errc; err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// run X11 连接只在本协程使用，协程固定在一个系统线程上
+func (w *consumeWatcher) run(errc chan<- error) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	defer close(w.done)
+
+	o := C.openOwner()
+	if o == nil {
+		errc <- fmt.Errorf("%w: 无法连接 X11", ErrConsumeUnsupported)
+		return
+	}
+	defer C.closeOwner(o)
+	errc <- nil
+
+	var (
+		offered  consumeOffer // 当前提供的内容
+		ownedAt  time.Time    // 取得选区的时间
+		notified bool         // 当前内容是否已发出读取通知
+	)
+	for {
+		select {
+		case <-w.stop:
+			w.handBack(o, offered)
+			return
+		case offer := <-w.offers:
+			data := offer.data
+			if len(data) == 0 {
+				data = []byte{0} // 空内容也需要有效指针
+			}
+			ok := C.own(o, unsafe.Pointer(&data[0]), C.int(len(offer.data)), cbool(offer.image)) == 1
+			if ok {
+				offered, ownedAt, notified = offer, time.Now(), false
+			}
+			offer.result <- ok
+			continue
+		default:
+		}
+
+		switch C.waitEvent(o, consumePollTimeout) {
+		case 1:
+			// 取得选区后立即读取的通常是其他剪贴板管理器，不视为粘贴
+			if notified || time.Since(ownedAt) < consumeGrace {
+				continue
+			}
+			notified = true
+			w.notify()
+		case 2:
+			log.Println("粘贴队列的内容已被其他程序的复制替换，停止检测粘贴")
+		case -1:
+			// 立即退出，done 关闭后 Offer 返回 false，监听器改为手动载入下一项
+			log.Println("粘贴检测的 X11 连接已断开")
+			return
+		}
+	}
+}
+
+// handBack 停止前仍持有选区时交给 golang.design/x/clipboard 继续提供，避免剪贴板被清空
+func (w *consumeWatcher) handBack(o *C.owner, offered consumeOffer) {
+	if C.owns(o) == 0 {
+		return
+	}
+	format := clipboard.FmtText
+	if offered.image {
+		format = clipboard.FmtImage
+	}
+	clipboard.Write(format, offered.data)
+}
+
+// cbool 转换为 C 的布尔整数
+func cbool(b bool) C.int {
+	if b {
+		return 1
+	}
+	return 0
+}
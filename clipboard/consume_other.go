@@ -0,0 +1,8 @@
+//go:build !linux || !cgo
+
+package clipboard
+
+// startConsumeWatcher 目前只支持 X11，其他平台由用户手动载入下一项
+func startConsumeWatcher() (*consumeWatcher, error) {
+	return nil, ErrConsumeUnsupported
+}
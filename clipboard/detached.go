@@ -0,0 +1,66 @@
+package clipboard
+
+import (
+	"clipboard/filelist"
+	"clipboard/imageproc"
+	"clipboard/model"
+	"clipboard/snippet"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.design/x/clipboard"
+)
+
+// WriteDetached 不启动监听器，直接将历史项写入剪贴板（命令行使用）
+// X11 下剪贴板内容由写入进程持有：有 xclip 时交给 xclip 后台进程，返回已关闭的通道；
+// 否则返回的通道在内容被其他程序覆盖后关闭，调用方需保持运行直到通道关闭
+func WriteDetached(item *model.ClipboardItem) (<-chan struct{}, error) {
+	data, mime, format, err := detachedData(item)
+	if err != nil {
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	close(done)
+	if err := writeClipboardMIME(data, mime); err == nil {
+		return done, nil
+	} else if !errors.Is(err, ErrImageMIMEUnsupported) {
+		return nil, err
+	}
+
+	if err := clipboard.Init(); err != nil {
+		return nil, fmt.Errorf("剪贴板初始化失败: %w", err)
+	}
+	return clipboard.Write(format, data), nil
+}
+
+// detachedData 准备写入剪贴板的数据、MIME 类型与格式
+func detachedData(item *model.ClipboardItem) ([]byte, string, clipboard.Format, error) {
+	switch item.Type {
+	case model.TypeText:
+		return []byte(item.Content), "UTF8_STRING", clipboard.FmtText, nil
+	case model.TypeSnippet:
+		content, err := snippet.Expand(item.Content, snippet.Context{})
+		if err != nil {
+			return nil, "", 0, fmt.Errorf("展开片段失败: %w", err)
+		}
+		return []byte(content), "UTF8_STRING", clipboard.FmtText, nil
+	case model.TypeFile:
+		paths := strings.Join(filelist.Resolve(item), filelist.Separator)
+		return []byte(paths), "UTF8_STRING", clipboard.FmtText, nil
+	case model.TypeImage:
+		data, err := os.ReadFile(item.ImagePath)
+		if err != nil {
+			return nil, "", 0, fmt.Errorf("读取图片失败: %w", err)
+		}
+		// 剪贴板图片统一使用 PNG
+		if data, _, err = imageproc.Process(data, imageproc.Options{Format: imageproc.FormatPNG}); err != nil {
+			return nil, "", 0, err
+		}
+		return data, imageproc.MIMEType(imageproc.FormatPNG), clipboard.FmtImage, nil
+	default:
+		return nil, "", 0, errors.New("不支持的内容类型")
+	}
+}
@@ -2,8 +2,16 @@ package clipboard
 
 import (
 	"bytes"
+	"clipboard/classify"
+	"clipboard/config"
+	"clipboard/filelist"
+	"clipboard/imageproc"
 	"clipboard/model"
+	"clipboard/rules"
+	"clipboard/sensitive"
+	"clipboard/snippet"
 	"clipboard/storage"
+	"clipboard/transform"
 	"errors"
 	"fmt"
 	"golang.design/x/clipboard"
@@ -12,36 +20,67 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 )
 
 // Monitor 剪贴板监听器
 type Monitor struct {
 	storage           storage.Storage             // 存储接口
+	config            *config.AppConfig           // 应用配置
 	processor         *Processor                  // 内容处理器（图片等复杂内容）
+	rules             *rules.Engine               // 捕获规则引擎
 	StopChan          chan struct{}               // 停止信号通道
 	changeChan        chan []*model.ClipboardItem // 变化通知通道
 	lastText          string                      // 上次文本内容
 	lastImageID       string                      // 上次图片ID
 	lastFileList      string                      // 上次文件列表
+	lastPrimaryText   string                      // 上次 PRIMARY 选区文本（独立去重）
+	primaryDisabled   bool                        // PRIMARY 选区不可用时停止轮询
+	lastPurge         time.Time                   // 上次清理过期项的时间
+	pauseMu           sync.Mutex                  // 保护暂停状态
+	paused            bool                        // 是否暂停捕获
+	pausedUntil       time.Time                   // 暂停截止时间，零值表示直到手动恢复
+	queueMu           sync.Mutex                  // 保护粘贴队列
+	queue             []*model.ClipboardItem      // 等待粘贴的项
+	queueCurrent      *model.ClipboardItem        // 当前已载入剪贴板的队列项
+	consume           *consumeWatcher             // 粘贴检测，首次启动粘贴队列时创建
+	consumeDisabled   bool                        // 当前环境不支持粘贴检测
 	isRunning         bool                        // 运行状态标识
 	isManualWrite     bool                        // 新增：标记是否为程序手动写入的图片
 	manualWriteExpire time.Time                   // 新增：手动写入标记的过期时间（避免永久屏蔽）
 }
 
 // NewMonitor 创建剪贴板监听器
-func NewMonitor(s storage.Storage) (*Monitor, error) {
+func NewMonitor(s storage.Storage, cfg *config.AppConfig) (*Monitor, error) {
 	processor, err := NewProcessor(s.GetImagePath())
 	if err != nil {
 		return nil, fmt.Errorf("初始化处理器失败: %w", err)
 	}
 
-	return &Monitor{
+	engine, err := rules.NewEngine(cfg.CaptureRules)
+	if err != nil {
+		log.Printf("部分捕获规则无效，已跳过: %v", err)
+	}
+
+	m := &Monitor{
 		storage:    s,
+		config:     cfg,
 		processor:  processor,
+		rules:      engine,
 		StopChan:   make(chan struct{}),
 		changeChan: make(chan []*model.ClipboardItem, 10),
-	}, nil
+	}
+
+	// 恢复上次保存的暂停状态
+	if p := cfg.CapturePause; p.Persist && p.Paused {
+		if p.Until.IsZero() || time.Now().Before(p.Until) {
+			m.paused = true
+			m.pausedUntil = p.Until
+		}
+	}
+
+	return m, nil
 }
 
 // Start 开始监听剪贴板变化
@@ -61,7 +100,10 @@ func (m *Monitor) Start() error {
 			case <-m.StopChan:
 				return
 			default:
+				m.checkPauseExpired()
 				m.checkClipboard()
+				m.checkPrimary()
+				m.purgeExpired()
 				time.Sleep(500 * time.Millisecond)
 			}
 		}
@@ -77,6 +119,10 @@ func (m *Monitor) Stop() {
 	}
 
 	close(m.StopChan)
+	m.queueMu.Lock()
+	consume := m.consume
+	m.queueMu.Unlock()
+	consume.Close()
 	time.Sleep(100 * time.Millisecond)
 }
 
@@ -85,16 +131,142 @@ func (m *Monitor) IsRunning() bool {
 	return m.isRunning
 }
 
+// Pause 暂停捕获，d 为 0 时直到调用 Resume 才恢复
+// 暂停期间仍会跟踪最新的剪贴板内容，恢复后不会补记暂停期间复制的内容
+func (m *Monitor) Pause(d time.Duration) {
+	m.pauseMu.Lock()
+	defer m.pauseMu.Unlock()
+
+	m.paused = true
+	m.pausedUntil = time.Time{}
+	if d > 0 {
+		m.pausedUntil = time.Now().Add(d)
+	}
+	log.Printf("剪贴板捕获已暂停，截止: %v", m.pausedUntil)
+}
+
+// Resume 恢复捕获
+func (m *Monitor) Resume() {
+	m.pauseMu.Lock()
+	defer m.pauseMu.Unlock()
+
+	m.paused = false
+	m.pausedUntil = time.Time{}
+	log.Println("剪贴板捕获已恢复")
+}
+
+// PauseState 返回是否暂停及暂停截止时间（零值表示直到手动恢复）
+func (m *Monitor) PauseState() (bool, time.Time) {
+	m.pauseMu.Lock()
+	defer m.pauseMu.Unlock()
+	return m.paused, m.pausedUntil
+}
+
+// isPaused 检查当前是否暂停捕获
+func (m *Monitor) isPaused() bool {
+	paused, _ := m.PauseState()
+	return paused
+}
+
+// checkPauseExpired 定时暂停到期后自动恢复，并通知界面刷新状态
+func (m *Monitor) checkPauseExpired() {
+	m.pauseMu.Lock()
+	expired := m.paused && !m.pausedUntil.IsZero() && time.Now().After(m.pausedUntil)
+	if expired {
+		m.paused = false
+		m.pausedUntil = time.Time{}
+	}
+	m.pauseMu.Unlock()
+
+	if !expired {
+		return
+	}
+	log.Println("暂停时间已到，剪贴板捕获自动恢复")
+	m.notifyChange("恢复捕获")
+}
+
+// notifyChange 重新加载历史并通知应用层刷新（通道满时丢弃）
+func (m *Monitor) notifyChange(reason string) {
+	items, err := m.storage.LoadItems()
+	if err != nil {
+		log.Printf("%s后加载历史失败: %v", reason, err)
+		return
+	}
+	select {
+	case m.changeChan <- items:
+	default:
+		fmt.Printf("通知通道已满，丢弃%s更新\n", reason)
+	}
+}
+
+// Done 停止时关闭的通道
+func (m *Monitor) Done() <-chan struct{} {
+	return m.StopChan
+}
+
 // ChangeChan 获取变化通知通道
 func (m *Monitor) ChangeChan() <-chan []*model.ClipboardItem {
 	return m.changeChan
 }
 
+// PasteOptions 写回剪贴板时的处理选项
+type PasteOptions struct {
+	Transforms []string          `json:"transforms,omitempty"` // 文本转换链（见 transform 包）
+	Inputs     map[string]string `json:"inputs,omitempty"`     // 片段 {{input:标签}} 占位符的输入值
+	Image      imageproc.Options `json:"image"`                // 图片格式转换、缩放与压缩选项
+}
+
 // SetContent 设置剪贴板内容
 func (m *Monitor) SetContent(item *model.ClipboardItem) error {
+	return m.SetContentWith(item, PasteOptions{})
+}
+
+// SetContentWith 按选项处理后设置剪贴板内容，成功后记录使用次数
+func (m *Monitor) SetContentWith(item *model.ClipboardItem, opts PasteOptions) error {
 	if item == nil {
 		return errors.New("无效的剪贴板项")
 	}
+	if err := m.writeContent(item, opts); err != nil {
+		return err
+	}
+
+	if item.ID != "" {
+		if err := m.storage.RecordUse(item.ID); err != nil {
+			log.Printf("记录使用次数失败: %v", err)
+		}
+	}
+	return nil
+}
+
+// writeContent 执行片段展开、转换后写入剪贴板
+func (m *Monitor) writeContent(item *model.ClipboardItem, opts PasteOptions) error {
+
+	// 展开片段占位符
+	content := item.Content
+	if item.Type == model.TypeSnippet {
+		current := string(clipboard.Read(clipboard.FmtText))
+		expanded, err := snippet.Expand(content, snippet.Context{
+			Clipboard: current,
+			Inputs:    opts.Inputs,
+		})
+		if err != nil {
+			return fmt.Errorf("展开片段失败: %w", err)
+		}
+		content = expanded
+	}
+
+	// 先执行转换，失败时不改动剪贴板
+	if len(opts.Transforms) > 0 {
+		if item.Type != model.TypeText && item.Type != model.TypeSnippet {
+			return errors.New("只有文本内容支持转换")
+		}
+		var err error
+		if content, err = transform.Apply(opts.Transforms, content); err != nil {
+			return err
+		}
+		log.Printf("粘贴转换: %s", transform.Describe(opts.Transforms))
+	}
+
 	log.Println("程序写入剪贴板，开始屏蔽检测")
 
 	// 设置手动写入标记，有效期延长至5秒（覆盖系统延迟）
@@ -102,8 +274,10 @@ func (m *Monitor) SetContent(item *model.ClipboardItem) error {
 	m.manualWriteExpire = time.Now().Add(5 * time.Second)
 
 	switch item.Type {
-	case model.TypeText:
-		clipboard.Write(clipboard.FmtText, []byte(item.Content))
+	case model.TypeText, model.TypeSnippet:
+		clipboard.Write(clipboard.FmtText, []byte(content))
+		// 转换后的内容不作为新历史项记录
+		m.lastText = content
 		return nil
 	case model.TypeImage:
 		if item.ImagePath == "" {
@@ -124,6 +298,22 @@ func (m *Monitor) SetContent(item *model.ClipboardItem) error {
 		}
 		imageID := m.processor.imageID(img.Width, img.Height, data)
 
+		// 按选项处理后写入剪贴板，处理失败时不改动剪贴板
+		if !opts.Image.IsZero() {
+			processed, format, err := imageproc.Process(data, opts.Image)
+			if err != nil {
+				return fmt.Errorf("图片处理失败: %w", err)
+			}
+			if err := m.processor.SetImageDataToClipboard(processed, format); err != nil {
+				return err
+			}
+			// 处理后的图片不作为新历史项记录
+			if cfg, _, err := image.DecodeConfig(bytes.NewReader(processed)); err == nil {
+				m.lastImageID = m.processor.imageID(cfg.Width, cfg.Height, processed)
+			}
+			return nil
+		}
+
 		// 写入剪贴板
 		err = m.processor.SetImageToClipboard(item.ImagePath)
 		if err != nil {
@@ -135,7 +325,10 @@ func (m *Monitor) SetContent(item *model.ClipboardItem) error {
 
 		return nil
 	case model.TypeFile:
-		clipboard.Write(clipboard.FmtText, []byte(item.Content))
+		// 原文件不存在时改用快照路径
+		paths := strings.Join(filelist.Resolve(item), filelist.Separator)
+		clipboard.Write(clipboard.FmtText, []byte(paths))
+		m.lastFileList = paths
 		return nil
 	default:
 		return errors.New("不支持的内容类型")
@@ -179,7 +372,7 @@ func (m *Monitor) checkClipboard() {
 	}
 
 	if text != "" && text != m.lastText && !isFile {
-		log.Printf("检测到文本变化: %s", text)
+		log.Printf("检测到文本变化，长度: %d", len(text))
 		m.handleTextChange(text)
 		return
 	}
@@ -225,15 +418,127 @@ func (m *Monitor) checkFilePaths(text string) (bool, string) {
 
 	// 如果有有效的文件路径，视为文件剪贴板内容
 	if len(validPaths) > 0 {
-		return true, strings.Join(validPaths, ";")
+		return true, strings.Join(validPaths, filelist.Separator)
 	}
 
 	return false, ""
 }
 
+// checkPrimary 检查 X11 PRIMARY 选区变化，按配置记录或与 CLIPBOARD 同步
+func (m *Monitor) checkPrimary() {
+	mode := m.primaryMode()
+	if mode == config.PrimaryModeIgnore || m.primaryDisabled {
+		return
+	}
+
+	text, err := readPrimary()
+	if err != nil {
+		if errors.Is(err, ErrPrimaryUnsupported) {
+			log.Printf("PRIMARY选区不可用，已停止检测: %v", err)
+			m.primaryDisabled = true
+		}
+		return
+	}
+	if strings.TrimSpace(text) == "" || text == m.lastPrimaryText {
+		return
+	}
+	m.lastPrimaryText = text
+	if m.isPaused() {
+		return
+	}
+
+	switch mode {
+	case config.PrimaryModeCapture:
+		log.Printf("检测到PRIMARY选区变化，长度: %d", len(text))
+		item := model.NewClipboardItem(model.TypeText, text, "")
+		item.Source = model.SourcePrimary
+		item.Subtype, item.Language = classify.Text(text)
+		if !m.applyRules(item, 0) {
+			return
+		}
+		sensitive.Mark(item, m.config.Sensitive)
+		items, err := m.storage.AddItem(item)
+		if err != nil {
+			fmt.Printf("保存PRIMARY选区失败: %v\n", err)
+			return
+		}
+
+		select {
+		case m.changeChan <- items:
+		default:
+			fmt.Println("通知通道已满，丢弃PRIMARY选区更新")
+		}
+	case config.PrimaryModeSync:
+		// 写入 CLIPBOARD 后由常规检测流程记录为文本项
+		if text != m.lastText {
+			log.Println("同步PRIMARY选区到CLIPBOARD")
+			clipboard.Write(clipboard.FmtText, []byte(text))
+		}
+	}
+}
+
+// primaryMode 返回当前 PRIMARY 选区处理方式
+func (m *Monitor) primaryMode() config.PrimaryMode {
+	if m.config == nil || m.config.PrimaryMode == "" {
+		return config.PrimaryModeIgnore
+	}
+	return m.config.PrimaryMode
+}
+
+// applyRules 对新项求值捕获规则，返回是否保留
+func (m *Monitor) applyRules(item *model.ClipboardItem, imageSize int64) bool {
+	matches := m.rules.Evaluate(rules.Input{
+		Type:      item.Type,
+		Text:      item.Content,
+		ImageSize: imageSize,
+	})
+	for _, match := range matches {
+		log.Printf("命中捕获规则: %s", match)
+	}
+	return rules.Apply(matches, item)
+}
+
+// purgeExpired 定期清理已过期的项
+func (m *Monitor) purgeExpired() {
+	if time.Since(m.lastPurge) < 30*time.Second {
+		return
+	}
+	m.lastPurge = time.Now()
+
+	removed, err := m.storage.PurgeExpired()
+	if err != nil {
+		log.Printf("清理过期项失败: %v", err)
+		return
+	}
+	if removed == 0 {
+		return
+	}
+	log.Printf("已清理 %d 个过期项", removed)
+	m.notifyChange("过期清理")
+}
+
 // handleTextChange 处理文本内容变化
 func (m *Monitor) handleTextChange(text string) {
+	if m.isPaused() {
+		// 暂停期间只记录最新内容，不写入历史
+		m.lastText = text
+		return
+	}
+
 	item := model.NewClipboardItem(model.TypeText, text, "")
+	item.Subtype, item.Language = classify.Text(text)
+	if !m.applyRules(item, 0) {
+		// 记录为已处理，避免每次轮询重复求值
+		m.lastText = text
+		return
+	}
+	if m.config != nil {
+		// 内容未命中检测器时，再看密码管理器是否通过 MIME 提示标记了密码
+		cfg := m.config.Sensitive
+		if _, found := sensitive.Mark(item, cfg); !found && cfg.Enabled && hasPasswordManagerHint() {
+			sensitive.MarkKind(item, sensitive.KindPassword, cfg)
+		}
+	}
 	items, err := m.storage.AddItem(item)
 	if err != nil {
 		fmt.Printf("保存文本失败: %v\n", err)
@@ -250,6 +555,15 @@ func (m *Monitor) handleTextChange(text string) {
 		}
 	}
 
+	// 同步模式下将 CLIPBOARD 文本写回 PRIMARY 选区（敏感内容不同步）
+	if m.primaryMode() == config.PrimaryModeSync && !item.Sensitive && text != m.lastPrimaryText {
+		if err := writePrimary(text); err != nil {
+			log.Printf("同步CLIPBOARD到PRIMARY选区失败: %v", err)
+		} else {
+			m.lastPrimaryText = text
+		}
+	}
+
 	// 通知应用层触发全量重建（忽略通道满的情况，确保重建优先级）
 	select {
 	case m.changeChan <- items:
@@ -268,6 +582,10 @@ func (m *Monitor) handleImageChange(imageID string) {
 		log.Printf("忽略重复图片ID: %s", imageID)
 		return
 	}
+	if m.isPaused() {
+		m.lastImageID = imageID
+		return
+	}
 	log.Printf("处理新图片，ID: %s", imageID)
 
 	// 读取图片数据
@@ -276,6 +594,30 @@ func (m *Monitor) handleImageChange(imageID string) {
 		return
 	}
 
+	// 先求值捕获规则，被丢弃的图片不落盘
+	item := model.NewClipboardItem(model.TypeImage, "图片内容", "")
+	if !m.applyRules(item, int64(len(imageData))) {
+		m.lastImageID = imageID
+		return
+	}
+
+	// 提取元数据（含感知哈希），与已有图片相似时只更新旧项的时间戳
+	if meta, err := m.processor.ImageMeta(imageData); err != nil {
+		log.Printf("提取图片元数据失败: %v", err)
+	} else {
+		item.ImageMeta = meta
+		if items, found := m.bumpSimilarImage(item); found {
+			m.lastImageID = imageID
+			select {
+			case m.changeChan <- items:
+				log.Printf("图片 %s 与已有项相似，已更新其时间戳", imageID)
+			default:
+				fmt.Println("通知通道已满，丢弃图片更新")
+			}
+			return
+		}
+	}
+
 	// 保存图片
 	imagePath, err := m.processor.SaveImageWithData(imageData)
 	if err != nil {
@@ -284,7 +626,7 @@ func (m *Monitor) handleImageChange(imageID string) {
 	}
 
 	// 保存记录
-	item := model.NewClipboardItem(model.TypeImage, "图片内容", imagePath)
+	item.ImagePath = imagePath
 	items, err := m.storage.AddItem(item)
 	if err != nil {
 		fmt.Printf("保存图片记录失败: %v\n", err)
@@ -303,16 +645,59 @@ func (m *Monitor) handleImageChange(imageID string) {
 	}
 }
 
+// bumpSimilarImage 相似图片去重开启时，查找并更新与新图片相似的已有项
+func (m *Monitor) bumpSimilarImage(item *model.ClipboardItem) ([]*model.ClipboardItem, bool) {
+	if m.config == nil || !m.config.ImageDedupe.Enabled {
+		return nil, false
+	}
+
+	items, found, err := m.storage.BumpSimilarImage(item.ImageHash, m.config.ImageDedupe.MaxDistance, item.Timestamp)
+	if err != nil {
+		log.Printf("查找相似图片失败: %v", err)
+		return nil, false
+	}
+	return items, found
+}
+
 // handleFileChange 处理文件内容变化
 func (m *Monitor) handleFileChange(fileList string) {
 	m.lastFileList = fileList
+	if m.isPaused() {
+		return
+	}
 	item := model.NewClipboardItem(model.TypeFile, fileList, "")
+	if !m.applyRules(item, 0) {
+		return
+	}
+
+	// 记录文件信息，开启快照时复制小文件，原文件移动或删除后仍可粘贴
+	item.Files = filelist.Collect(strings.Split(fileList, filelist.Separator))
+	if cfg := m.config; cfg != nil && cfg.FileSnapshot.Enabled {
+		root := filelist.SnapshotDir(m.storage.GetImagePath())
+		if err := filelist.Snapshot(item, root, cfg.FileSnapshot.MaxFileSize); err != nil {
+			log.Printf("创建文件快照失败: %v", err)
+		}
+	}
+
 	items, err := m.storage.AddItem(item)
 	if err != nil {
+		filelist.RemoveSnapshots(item)
 		fmt.Printf("保存文件记录失败: %v\n", err)
 		return
 	}
 
+	// 与已有项重复时未保存新项，清理刚创建的快照
+	saved := false
+	for _, existing := range items {
+		if existing.ID == item.ID {
+			saved = true
+			break
+		}
+	}
+	if !saved {
+		filelist.RemoveSnapshots(item)
+	}
+
 	select {
 	case m.changeChan <- items:
 	default:
@@ -366,5 +751,6 @@ func (p *Processor) SaveImageWithData(imageData []byte) (string, error) {
 	}
 
 	log.Printf(" 已保存 %s：%s", strings.ToUpper(ext), fullPath)
+	p.generateThumbnail(fullPath)
 	return fullPath, nil
 }
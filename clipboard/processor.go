@@ -2,6 +2,10 @@ package clipboard
 
 import (
 	"bytes"
+	"clipboard/imagemeta"
+	"clipboard/imageproc"
+	"clipboard/model"
+	"clipboard/thumbnail"
 	"crypto/md5"
 	"encoding/hex"
 	"errors"
@@ -9,8 +13,6 @@ import (
 	"github.com/skratchdot/open-golang/open"
 	"golang.design/x/clipboard"
 	"image"
-	"image/draw"
-	"image/gif"
 	"image/jpeg"
 	"image/png"
 	"log"
@@ -21,11 +23,16 @@ import (
 
 // 预定义错误变量
 var (
-	ErrNoImageData    = errors.New("剪贴板中没有图片数据")
-	ErrUnsupportedImg = errors.New("不支持的图片格式")
-	ErrFileNotFound   = errors.New("图片文件不存在")
+	ErrNoImageData          = errors.New("剪贴板中没有图片数据")
+	ErrUnsupportedImg       = errors.New("不支持的图片格式")
+	ErrFileNotFound         = errors.New("图片文件不存在")
+	ErrPrimaryUnsupported   = errors.New("当前环境不支持PRIMARY选区（需要 xclip 或 xsel）")
+	ErrImageMIMEUnsupported = errors.New("当前环境只能以 PNG 格式写入剪贴板图片（其他格式需要 xclip）")
 )
 
+// passwordManagerHint 密码管理器标记敏感内容使用的 MIME 类型
+const passwordManagerHint = "x-kde-passwordManagerHint"
+
 // Processor 剪贴板内容处理器
 type Processor struct {
 	imagePath string // 图片存储目录
@@ -123,7 +130,15 @@ func (p *Processor) SaveImage() (string, error) {
 	}
 
 	log.Printf("图片已保存为绝对路径: %s", filePath) // 新增日志，便于调试
-	return filePath, nil                   // 返回绝对路径
+	p.generateThumbnail(filePath)
+	return filePath, nil // 返回绝对路径
+}
+
+// generateThumbnail 为新保存的图片生成缩略图，失败时由界面按需重新生成
+func (p *Processor) generateThumbnail(imagePath string) {
+	if _, err := thumbnail.Generate(imagePath); err != nil {
+		log.Printf("生成缩略图失败: %v", err)
+	}
 }
 
 // SetImageToClipboard 将图片文件设置到剪贴板
@@ -178,6 +193,24 @@ func (p *Processor) SetImageToClipboard(imagePath string) error {
 	return nil
 }
 
+// SetImageDataToClipboard 将处理后的图片数据按格式写入剪贴板
+func (p *Processor) SetImageDataToClipboard(data []byte, format string) error {
+	if len(data) == 0 {
+		return ErrNoImageData
+	}
+
+	if format != imageproc.FormatPNG {
+		if err := writeClipboardMIME(data, imageproc.MIMEType(format)); err != nil {
+			return err
+		}
+	} else {
+		clipboard.Write(clipboard.FmtImage, data)
+	}
+
+	log.Printf("处理后的图片已写入剪贴板（格式：%s，大小：%d KB）", format, len(data)/1024)
+	return nil
+}
+
 // OpenImage 打开图片文件（用于预览）
 func (p *Processor) OpenImage(imagePath string) error {
 	if _, err := os.Stat(imagePath); os.IsNotExist(err) {
@@ -187,6 +220,12 @@ func (p *Processor) OpenImage(imagePath string) error {
 	return open.Start(imagePath)
 }
 
+// ImageMeta 提取图片的尺寸、格式、主色调和感知哈希
+// 感知哈希对重新编码、轻微压缩不敏感，用于相似图片去重
+func (p *Processor) ImageMeta(data []byte) (model.ImageMeta, error) {
+	return imagemeta.FromBytes(data)
+}
+
 // 生成图片唯一标识
 func (p *Processor) imageID(width, height int, data []byte) string {
 	// 1. 计算图片内容的MD5哈希（确保相同内容哈希一致）
@@ -197,19 +236,7 @@ func (p *Processor) imageID(width, height int, data []byte) string {
 	return id
 }
 
-// 编码GIF图片（使用标准库自动处理调色板）
+// 编码GIF图片
 func (p *Processor) encodeGIF(file *os.File, img image.Image) error {
-	bounds := img.Bounds()
-
-	// 创建带自动生成调色板的图像
-	palettedImg := image.NewPaletted(bounds, nil)
-
-	// 复制图像数据
-	draw.Draw(palettedImg, bounds, img, bounds.Min, draw.Src)
-
-	// 编码为GIF
-	return gif.EncodeAll(file, &gif.GIF{
-		Image: []*image.Paletted{palettedImg},
-		Delay: []int{0},
-	})
+	return imageproc.Encode(file, img, imageproc.FormatGIF, 0)
 }
@@ -0,0 +1,201 @@
+package clipboard
+
+import (
+	"bytes"
+	"clipboard/filelist"
+	"clipboard/model"
+	"errors"
+	"image"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// QueueMode 粘贴队列的出队顺序
+type QueueMode string
+
+const (
+	QueueFIFO QueueMode = "fifo" // 按选中顺序依次粘贴
+	QueueLIFO QueueMode = "lifo" // 从最后选中的开始粘贴
+)
+
+// ErrQueueEmpty 粘贴队列为空
+var ErrQueueEmpty = errors.New("粘贴队列为空")
+
+// StartQueue 启动粘贴队列，立即将第一项写入剪贴板
+// 之后其他程序每粘贴一次（X11 下检测到读取剪贴板）或调用 AdvanceQueue 时载入下一项
+func (m *Monitor) StartQueue(items []*model.ClipboardItem, mode QueueMode) error {
+	if len(items) == 0 {
+		return ErrQueueEmpty
+	}
+
+	queue := append([]*model.ClipboardItem(nil), items...)
+	if mode == QueueLIFO {
+		for i, j := 0, len(queue)-1; i < j; i, j = i+1, j-1 {
+			queue[i], queue[j] = queue[j], queue[i]
+		}
+	}
+
+	m.queueMu.Lock()
+	m.queue = queue
+	m.queueCurrent = nil
+	m.queueMu.Unlock()
+	m.startConsumeWatcher()
+
+	log.Printf("粘贴队列已启动，共 %d 项（%s）", len(queue), mode)
+	return m.AdvanceQueue()
+}
+
+// AdvanceQueue 将队列中的下一项写入剪贴板，队列耗尽后自动结束
+func (m *Monitor) AdvanceQueue() error {
+	m.queueMu.Lock()
+	if len(m.queue) == 0 {
+		finished := m.queueCurrent != nil
+		m.queueCurrent = nil
+		m.queueMu.Unlock()
+		if finished {
+			log.Println("粘贴队列已完成")
+			m.notifyChange("粘贴队列完成")
+		}
+		return ErrQueueEmpty
+	}
+	next := m.queue[0]
+	m.queue = m.queue[1:]
+	m.queueCurrent = next
+	m.queueMu.Unlock()
+
+	if !m.offerQueueItem(next) {
+		if err := m.SetContent(next); err != nil {
+			return err
+		}
+	}
+	m.notifyChange("粘贴队列前进")
+	return nil
+}
+
+// startConsumeWatcher 启动粘贴检测，已启动或环境不支持时不做处理
+func (m *Monitor) startConsumeWatcher() {
+	m.queueMu.Lock()
+	defer m.queueMu.Unlock()
+	if m.consume != nil || m.consumeDisabled {
+		return
+	}
+	watcher, err := startConsumeWatcher()
+	if err != nil {
+		log.Printf("%v，粘贴队列需要手动载入下一项", err)
+		m.consumeDisabled = true
+		return
+	}
+	m.consume = watcher
+	go m.advanceOnConsume(watcher)
+}
+
+// advanceOnConsume 其他程序粘贴了当前队列项后载入下一项
+func (m *Monitor) advanceOnConsume(watcher *consumeWatcher) {
+	for {
+		select {
+		case <-watcher.Consumed():
+			if current, _ := m.QueueState(); current == nil {
+				continue
+			}
+			log.Println("检测到粘贴，载入粘贴队列下一项")
+			if err := m.AdvanceQueue(); err != nil && !errors.Is(err, ErrQueueEmpty) {
+				log.Printf("载入粘贴队列下一项失败: %v", err)
+			}
+		case <-watcher.done:
+			m.dropConsumeWatcher(watcher)
+			return
+		}
+	}
+}
+
+// dropConsumeWatcher 粘贴检测意外退出（如 X11 连接断开）后不再使用，粘贴队列改为手动载入下一项
+func (m *Monitor) dropConsumeWatcher(watcher *consumeWatcher) {
+	select {
+	case <-watcher.stop:
+		return // 由 Stop 关闭
+	default:
+	}
+	m.queueMu.Lock()
+	defer m.queueMu.Unlock()
+	if m.consume == watcher {
+		log.Println("粘贴检测已停止，粘贴队列需要手动载入下一项")
+		m.consume = nil
+		m.consumeDisabled = true
+	}
+}
+
+// offerQueueItem 由粘贴检测持有选区并提供队列项，返回 false 时改为常规写入（无法检测粘贴）
+// 只支持文本、文件列表与 PNG 图片，片段需要展开占位符，仍按常规方式写入
+func (m *Monitor) offerQueueItem(item *model.ClipboardItem) bool {
+	m.queueMu.Lock()
+	watcher := m.consume
+	m.queueMu.Unlock()
+	if watcher == nil {
+		return false
+	}
+
+	var (
+		data    []byte
+		isImage bool
+	)
+	switch item.Type {
+	case model.TypeText:
+		data = []byte(item.Content)
+	case model.TypeFile:
+		data = []byte(strings.Join(filelist.Resolve(item), filelist.Separator))
+	case model.TypeImage:
+		png, err := os.ReadFile(item.ImagePath)
+		if err != nil || !isPNG(png) {
+			return false
+		}
+		data, isImage = png, true
+	default:
+		return false
+	}
+
+	// 与 writeContent 一样屏蔽检测，避免将队列项再次记录为新历史项
+	m.isManualWrite = true
+	m.manualWriteExpire = time.Now().Add(5 * time.Second)
+	if !watcher.Offer(data, isImage) {
+		return false
+	}
+	switch item.Type {
+	case model.TypeText:
+		m.lastText = item.Content
+	case model.TypeFile:
+		m.lastFileList = string(data)
+	case model.TypeImage:
+		if cfg, _, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+			m.lastImageID = m.processor.imageID(cfg.Width, cfg.Height, data)
+		}
+	}
+	if item.ID != "" {
+		if err := m.storage.RecordUse(item.ID); err != nil {
+			log.Printf("记录使用次数失败: %v", err)
+		}
+	}
+	return true
+}
+
+// isPNG 是否为 PNG 数据
+func isPNG(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n"))
+}
+
+// ClearQueue 清空粘贴队列
+func (m *Monitor) ClearQueue() {
+	m.queueMu.Lock()
+	m.queue = nil
+	m.queueCurrent = nil
+	m.queueMu.Unlock()
+	m.notifyChange("粘贴队列清空")
+}
+
+// QueueState 返回当前已载入剪贴板的项和等待中的项
+func (m *Monitor) QueueState() (*model.ClipboardItem, []*model.ClipboardItem) {
+	m.queueMu.Lock()
+	defer m.queueMu.Unlock()
+	return m.queueCurrent, append([]*model.ClipboardItem(nil), m.queue...)
+}
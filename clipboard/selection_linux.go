@@ -0,0 +1,86 @@
+package clipboard
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// readPrimary 读取 X11 PRIMARY 选区文本（依次尝试 xclip、xsel）
+func readPrimary() (string, error) {
+	if path, err := exec.LookPath("xclip"); err == nil {
+		out, err := exec.Command(path, "-selection", "primary", "-o").Output()
+		if err != nil {
+			// 选区为空时 xclip 以非零状态退出，视为无内容
+			return "", nil
+		}
+		return string(out), nil
+	}
+
+	if path, err := exec.LookPath("xsel"); err == nil {
+		out, err := exec.Command(path, "--primary", "--output").Output()
+		if err != nil {
+			return "", nil
+		}
+		return string(out), nil
+	}
+
+	return "", ErrPrimaryUnsupported
+}
+
+// writePrimary 写入 X11 PRIMARY 选区文本
+func writePrimary(text string) error {
+	var cmd *exec.Cmd
+	if path, err := exec.LookPath("xclip"); err == nil {
+		cmd = exec.Command(path, "-selection", "primary", "-i")
+	} else if path, err := exec.LookPath("xsel"); err == nil {
+		cmd = exec.Command(path, "--primary", "--input")
+	} else {
+		return ErrPrimaryUnsupported
+	}
+
+	// xclip/xsel 会 fork 子进程持有选区，不能接管其输出管道，否则 Run 会一直阻塞
+	cmd.Stdin = strings.NewReader(text)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("写入PRIMARY选区失败: %w", err)
+	}
+	return nil
+}
+
+// writeClipboardMIME 通过 xclip 以指定 MIME 类型写入 CLIPBOARD
+// 内容由 xclip 的后台进程持有，调用方退出后仍然有效
+func writeClipboardMIME(data []byte, mime string) error {
+	path, err := exec.LookPath("xclip")
+	if err != nil {
+		return ErrImageMIMEUnsupported
+	}
+
+	// 同 writePrimary，不接管 xclip 的输出管道
+	cmd := exec.Command(path, "-selection", "clipboard", "-t", mime, "-i")
+	cmd.Stdin = bytes.NewReader(data)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("写入 %s 内容失败: %w", mime, err)
+	}
+	return nil
+}
+
+// hasPasswordManagerHint 检查 CLIPBOARD 是否带有密码管理器提示（x-kde-passwordManagerHint）
+// KeePassXC、KWallet 等在复制密码时会附带该 MIME 类型
+func hasPasswordManagerHint() bool {
+	path, err := exec.LookPath("xclip")
+	if err != nil {
+		return false
+	}
+
+	out, err := exec.Command(path, "-selection", "clipboard", "-t", "TARGETS", "-o").Output()
+	if err != nil || !strings.Contains(string(out), passwordManagerHint) {
+		return false
+	}
+
+	hint, err := exec.Command(path, "-selection", "clipboard", "-t", passwordManagerHint, "-o").Output()
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(string(hint)) == "secret"
+}
@@ -0,0 +1,23 @@
+//go:build !linux
+
+package clipboard
+
+// readPrimary 非 Linux 平台没有 PRIMARY 选区
+func readPrimary() (string, error) {
+	return "", ErrPrimaryUnsupported
+}
+
+// writePrimary 非 Linux 平台没有 PRIMARY 选区
+func writePrimary(string) error {
+	return ErrPrimaryUnsupported
+}
+
+// writeClipboardMIME 非 Linux 平台不支持指定 MIME 类型写入
+func writeClipboardMIME([]byte, string) error {
+	return ErrImageMIMEUnsupported
+}
+
+// hasPasswordManagerHint 非 Linux 平台无法读取任意 MIME 类型
+func hasPasswordManagerHint() bool {
+	return false
+}
@@ -2,17 +2,60 @@ package main
 
 import (
 	"clipboard/app"
+	"clipboard/cli"
+	"clipboard/instance"
+	"errors"
 	"fmt"
+	"log"
+	"os"
 )
 
 func main() {
+	// 后台服务模式：只捕获剪贴板并提供本地接口，不启动图形界面
+	if len(os.Args) > 1 && os.Args[1] == "daemon" {
+		if err := app.RunDaemon(); err != nil {
+			fmt.Fprintf(os.Stderr, "后台服务运行失败: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// 带子命令时以命令行模式运行，不启动图形界面
+	if len(os.Args) > 1 && cli.IsCommand(os.Args[1]) {
+		os.Exit(cli.Run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+	}
+
+	launch, err := instance.ParseArgs(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v（clipboard help 查看用法）\n", err)
+		os.Exit(2)
+	}
+
+	// 单实例：已有界面在运行时把命令转发给它后退出
+	inst, err := instance.Acquire()
+	if errors.Is(err, instance.ErrRunning) {
+		if err := instance.Send(launch); err != nil {
+			fmt.Fprintf(os.Stderr, "转发给运行中的实例失败: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if err != nil {
+		log.Printf("单实例检测失败，继续启动: %v", err)
+	}
+
 	// 创建应用
-	application, err := app.New()
+	application, err := app.New(inst)
 	if err != nil {
 		fmt.Printf("创建应用失败: %v\n", err)
 		return
 	}
 
+	// 执行启动参数中的命令（显示窗口由 Run 负责）
+	if launch.Action != instance.ActionShow {
+		application.Execute(launch)
+	}
+
 	// 运行应用
 	application.Run()
 }
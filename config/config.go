@@ -2,9 +2,12 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
+	"maps"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 // StorageType 存储类型
@@ -15,6 +18,124 @@ const (
 	StorageTypeMySQL StorageType = "mysql"
 )
 
+// PrimaryMode X11 PRIMARY 选区处理方式
+type PrimaryMode string
+
+const (
+	PrimaryModeIgnore  PrimaryMode = "ignore"  // 忽略 PRIMARY 选区
+	PrimaryModeCapture PrimaryMode = "capture" // 将 PRIMARY 选区记录到历史
+	PrimaryModeSync    PrimaryMode = "sync"    // PRIMARY 与 CLIPBOARD 双向同步
+)
+
+// HotkeyAction 全局快捷键的动作
+type HotkeyAction string
+
+const (
+	HotkeyActionPicker HotkeyAction = "picker" // 打开快速粘贴窗口
+	HotkeyActionWindow HotkeyAction = "window" // 显示主窗口
+)
+
+// ShortcutAction 窗口内快捷键的动作
+type ShortcutAction string
+
+const (
+	ShortcutFocusSearch    ShortcutAction = "focusSearch"    // 聚焦搜索框
+	ShortcutCopySelected   ShortcutAction = "copySelected"   // 将当前项写回剪贴板
+	ShortcutDeleteSelected ShortcutAction = "deleteSelected" // 删除当前项
+	ShortcutToggleFavorite ShortcutAction = "toggleFavorite" // 切换当前项的收藏状态
+	ShortcutNextTab        ShortcutAction = "nextTab"        // 切换到下一个标签页
+	ShortcutPrevTab        ShortcutAction = "prevTab"        // 切换到上一个标签页
+	ShortcutOpenSettings   ShortcutAction = "openSettings"   // 打开设置页
+	ShortcutUndo           ShortcutAction = "undo"           // 撤销上一次删除或收藏
+	ShortcutQueueNext      ShortcutAction = "queueNext"      // 载入粘贴队列的下一项（队列进行中时全局有效）
+)
+
+// DefaultShortcuts 窗口内快捷键的默认绑定
+var DefaultShortcuts = map[ShortcutAction]string{
+	ShortcutFocusSearch:    "Ctrl+F",
+	ShortcutCopySelected:   "Ctrl+C",
+	ShortcutDeleteSelected: "Ctrl+Delete",
+	ShortcutToggleFavorite: "Ctrl+D",
+	ShortcutNextTab:        "Ctrl+Tab",
+	ShortcutPrevTab:        "Ctrl+Shift+Tab",
+	ShortcutOpenSettings:   "Ctrl+Comma",
+	ShortcutUndo:           "Ctrl+Z",
+	ShortcutQueueNext:      "Ctrl+Alt+N", // 队列进行中时注册为全局快捷键，避开常用程序的 Ctrl+Shift+N
+}
+
+// RuleAction 捕获规则命中后的动作
+type RuleAction string
+
+const (
+	RuleActionDrop     RuleAction = "drop"     // 丢弃，不写入历史
+	RuleActionExpire   RuleAction = "expire"   // 保存但在 ExpireMinutes 分钟后过期
+	RuleActionTag      RuleAction = "tag"      // 自动添加标签 Tag
+	RuleActionFavorite RuleAction = "favorite" // 自动收藏
+)
+
+// CaptureRule 捕获规则：所有已设置的条件同时满足时命中
+type CaptureRule struct {
+	Name           string     `json:"name"`
+	Enabled        bool       `json:"enabled"`
+	Types          []string   `json:"types,omitempty"`          // 适用类型 text/image/file，为空表示全部
+	Include        string     `json:"include,omitempty"`        // 内容需匹配的正则
+	Exclude        string     `json:"exclude,omitempty"`        // 内容不能匹配的正则
+	MinLength      int        `json:"minLength,omitempty"`      // 文本最小字符数
+	MaxLength      int        `json:"maxLength,omitempty"`      // 文本最大字符数
+	MaxImageSize   int64      `json:"maxImageSize,omitempty"`   // 图片超过该字节数时命中
+	WhitespaceOnly bool       `json:"whitespaceOnly,omitempty"` // 仅在文本全为空白时命中
+	Action         RuleAction `json:"action"`
+	ExpireMinutes  int        `json:"expireMinutes,omitempty"` // expire 动作的有效期
+	Tag            string     `json:"tag,omitempty"`           // tag 动作添加的标签
+}
+
+// SensitiveConfig 敏感内容检测配置
+type SensitiveConfig struct {
+	Enabled       bool `json:"enabled"`       // 是否检测密码、令牌、私钥、卡号等敏感内容
+	ExpireMinutes int  `json:"expireMinutes"` // 敏感内容的保留时长（分钟）
+}
+
+// ImageDedupeConfig 相似图片去重配置
+type ImageDedupeConfig struct {
+	Enabled     bool `json:"enabled"`     // 是否按感知哈希合并相似图片，默认关闭以免内容略有不同的截图被合并
+	MaxDistance int  `json:"maxDistance"` // 视为重复的最大汉明距离（0-64，0 表示哈希完全相同）
+}
+
+// DefaultImageDedupeDistance 只容许重新编码造成的差异，内容有变化的截图不会被视为重复
+const DefaultImageDedupeDistance = 2
+
+// FileSnapshotConfig 文件快照配置
+type FileSnapshotConfig struct {
+	Enabled     bool  `json:"enabled"`     // 是否复制被复制的小文件，原文件删除后仍可粘贴
+	MaxFileSize int64 `json:"maxFileSize"` // 单个文件的快照大小上限（字节）
+}
+
+// HTTPAPIConfig 本地 HTTP 接口配置
+type HTTPAPIConfig struct {
+	Enabled bool   `json:"enabled"` // 是否启用供编辑器插件、启动器、浏览器扩展使用的 HTTP 接口
+	Addr    string `json:"addr"`    // 监听地址，只允许回环地址
+	Token   string `json:"token"`   // 访问令牌，请求需携带 Authorization: Bearer <令牌>
+}
+
+// TrayConfig 系统托盘配置
+type TrayConfig struct {
+	Enabled     bool `json:"enabled"`     // 是否显示托盘图标，启用时关闭窗口只最小化到托盘
+	RecentItems int  `json:"recentItems"` // 托盘菜单中显示的最近项数量
+}
+
+// CapturePause 捕获暂停状态
+type CapturePause struct {
+	Persist bool      `json:"persist"` // 重启后是否保持暂停状态
+	Paused  bool      `json:"paused"`
+	Until   time.Time `json:"until"` // 暂停截止时间，零值表示直到手动恢复
+}
+
+// TransformPreset 命名的粘贴转换预设
+type TransformPreset struct {
+	Name  string   `json:"name"`
+	Steps []string `json:"steps"` // 按顺序执行的转换名称
+}
+
 // StorageConfig 存储配置
 type StorageConfig struct {
 	Type       StorageType `json:"type"`
@@ -35,8 +156,19 @@ type MySQLConfig struct {
 
 // AppConfig 应用配置
 type AppConfig struct {
-	Storage StorageConfig `json:"storage"`
-	Hotkey  string        `json:"hotkey"`
+	Storage          StorageConfig             `json:"storage"`
+	Hotkey           string                    `json:"hotkey"`       // 全局快捷键，如 "Ctrl+Alt+V"，为空时不注册
+	HotkeyAction     HotkeyAction              `json:"hotkeyAction"` // 全局快捷键的动作
+	Shortcuts        map[ShortcutAction]string `json:"shortcuts"`    // 窗口内快捷键，值为空表示禁用
+	PrimaryMode      PrimaryMode               `json:"primaryMode"`  // PRIMARY 选区处理方式（仅 Linux/X11）
+	CaptureRules     []CaptureRule             `json:"captureRules"` // 捕获规则，按顺序求值
+	Sensitive        SensitiveConfig           `json:"sensitive"`
+	ImageDedupe      ImageDedupeConfig         `json:"imageDedupe"`
+	FileSnapshot     FileSnapshotConfig        `json:"fileSnapshot"`
+	HTTPAPI          HTTPAPIConfig             `json:"httpApi"`
+	Tray             TrayConfig                `json:"tray"`
+	CapturePause     CapturePause              `json:"capturePause"`
+	TransformPresets []TransformPreset         `json:"transformPresets"` // 粘贴转换预设
 }
 
 // ConfigPath 配置文件路径
@@ -46,12 +178,16 @@ func configPath() string {
 		return filepath.Join(".", "config.json")
 	}
 
+	// 配置中有 HTTP 接口令牌和数据库密码，目录只允许当前用户访问（旧版本创建的目录一并收紧）
 	configDir := filepath.Join(appDataDir, "clipboard-manager")
-	err = os.MkdirAll(configDir, 0755)
+	err = os.MkdirAll(configDir, 0700)
 	if err != nil {
 		log.Printf("创建配置目录失败: %v，将使用当前目录", err)
 		return filepath.Join(".", "config.json")
 	}
+	if err := os.Chmod(configDir, 0700); err != nil {
+		log.Printf("设置配置目录权限失败: %v", err)
+	}
 	return filepath.Join(configDir, "config.json")
 
 }
@@ -67,7 +203,8 @@ func Load() (*AppConfig, error) {
 		return nil, err
 	}
 
-	var config AppConfig
+	// 以默认配置为底，旧配置文件中缺少的字段保持默认值
+	config := *defaultConfig()
 	if err := json.Unmarshal(data, &config); err != nil {
 		return nil, err
 	}
@@ -76,6 +213,44 @@ func Load() (*AppConfig, error) {
 		config.Storage.MaxItems = 100
 	}
 
+	if config.PrimaryMode == "" {
+		config.PrimaryMode = PrimaryModeIgnore
+	}
+
+	if config.HotkeyAction == "" {
+		config.HotkeyAction = HotkeyActionPicker
+	}
+
+	// 补全新增动作的默认快捷键（用户清空的绑定保持为空）
+	if config.Shortcuts == nil {
+		config.Shortcuts = make(map[ShortcutAction]string)
+	}
+	for action, combo := range DefaultShortcuts {
+		if _, ok := config.Shortcuts[action]; !ok {
+			config.Shortcuts[action] = combo
+		}
+	}
+
+	if config.Sensitive.ExpireMinutes <= 0 {
+		config.Sensitive.ExpireMinutes = 60
+	}
+
+	if config.ImageDedupe.MaxDistance < 0 || config.ImageDedupe.MaxDistance > 64 {
+		config.ImageDedupe.MaxDistance = DefaultImageDedupeDistance
+	}
+
+	if config.FileSnapshot.MaxFileSize <= 0 {
+		config.FileSnapshot.MaxFileSize = 10 << 20
+	}
+
+	if config.HTTPAPI.Addr == "" {
+		config.HTTPAPI.Addr = DefaultHTTPAPIAddr
+	}
+
+	if config.Tray.RecentItems <= 0 {
+		config.Tray.RecentItems = 10
+	}
+
 	if !config.Storage.CustomPath {
 		appDataDir, _ := os.UserConfigDir()
 		config.Storage.JSONPath = filepath.Join(appDataDir, "clipboard-manager", "history")
@@ -90,9 +265,17 @@ func Save(config *AppConfig) error {
 		log.Printf("配置序列化为JSON失败: %v", err) // 记录序列化错误
 		return err
 	}
-	return os.WriteFile(configPath(), data, 0644)
+	// WriteFile 只对新文件应用权限，旧版本以 0644 创建的文件先收紧再写入
+	path := configPath()
+	if err := os.Chmod(path, 0600); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("设置配置文件权限失败: %w", err)
+	}
+	return os.WriteFile(path, data, 0600)
 }
 
+// DefaultHTTPAPIAddr HTTP 接口的默认监听地址
+const DefaultHTTPAPIAddr = "127.0.0.1:8765"
+
 // 默认配置
 func defaultConfig() *AppConfig {
 	appDataDir, _ := os.UserConfigDir()
@@ -112,6 +295,29 @@ func defaultConfig() *AppConfig {
 			},
 			MaxItems: 100,
 		},
-		Hotkey: "Ctrl+Shift+V",
+		Hotkey:       "Ctrl+Alt+V", // 不使用终端的粘贴键 Ctrl+Shift+V
+		HotkeyAction: HotkeyActionPicker,
+		Shortcuts:    maps.Clone(DefaultShortcuts),
+		PrimaryMode:  PrimaryModeIgnore,
+		Sensitive: SensitiveConfig{
+			Enabled:       true,
+			ExpireMinutes: 60,
+		},
+		ImageDedupe: ImageDedupeConfig{
+			Enabled:     false,
+			MaxDistance: DefaultImageDedupeDistance,
+		},
+		FileSnapshot: FileSnapshotConfig{
+			Enabled:     false,
+			MaxFileSize: 10 << 20,
+		},
+		HTTPAPI: HTTPAPIConfig{
+			Enabled: false,
+			Addr:    DefaultHTTPAPIAddr,
+		},
+		Tray: TrayConfig{
+			Enabled:     true,
+			RecentItems: 10,
+		},
 	}
 }
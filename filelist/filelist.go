@@ -0,0 +1,185 @@
+package filelist
+
+import (
+	"clipboard/model"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// hashLimit 超过该大小的文件不计算哈希，避免捕获时长时间读盘
+const hashLimit = 64 << 20
+
+// snapshotDirName 快照目录名，与图片目录同级
+const snapshotDirName = "file_snapshots"
+
+// Separator 文件项 Content 中路径的分隔符
+const Separator = ";"
+
+// Collect 读取路径的文件信息，不存在的路径被跳过
+func Collect(paths []string) []model.FileEntry {
+	var entries []model.FileEntry
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		entry := model.FileEntry{
+			Path:    path,
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+			Mode:    uint32(info.Mode()),
+			IsDir:   info.IsDir(),
+		}
+		if info.Mode().IsRegular() && info.Size() <= hashLimit {
+			if hash, err := hashFile(path); err == nil {
+				entry.Hash = hash
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// SnapshotDir 返回快照根目录（与图片目录同级）
+func SnapshotDir(imageDir string) string {
+	return filepath.Join(filepath.Dir(imageDir), snapshotDirName)
+}
+
+// Snapshot 将不超过 maxSize 的普通文件复制到 root/<itemID>/ 下，并记录快照路径
+// 单个文件复制失败不影响其他文件，返回遇到的第一个错误
+func Snapshot(item *model.ClipboardItem, root string, maxSize int64) error {
+	dir := filepath.Join(root, item.ID)
+	var firstErr error
+	for i := range item.Files {
+		entry := &item.Files[i]
+		if entry.IsDir || !os.FileMode(entry.Mode).IsRegular() || entry.Size > maxSize {
+			continue
+		}
+
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("创建快照目录失败: %w", err)
+		}
+		// 同名文件加序号区分
+		dst := filepath.Join(dir, fmt.Sprintf("%d_%s", i, filepath.Base(entry.Path)))
+		if err := copyFile(entry.Path, dst); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("复制快照失败（%s）: %w", entry.Path, err)
+			}
+			continue
+		}
+		entry.Snapshot = dst
+	}
+	return firstErr
+}
+
+// RemoveSnapshots 删除项的快照文件
+func RemoveSnapshots(item *model.ClipboardItem) {
+	for _, entry := range item.Files {
+		if entry.Snapshot == "" {
+			continue
+		}
+		os.Remove(entry.Snapshot)
+		// 目录为空时一并删除（非空时 Remove 失败，忽略即可）
+		os.Remove(filepath.Dir(entry.Snapshot))
+	}
+}
+
+// Entries 返回项的文件列表，旧版本只记录了 Content 的项按分隔符拆分
+func Entries(item *model.ClipboardItem) []model.FileEntry {
+	if len(item.Files) > 0 {
+		return item.Files
+	}
+	var entries []model.FileEntry
+	for _, path := range strings.Split(item.Content, Separator) {
+		if path = strings.TrimSpace(path); path != "" {
+			entries = append(entries, model.FileEntry{Path: path})
+		}
+	}
+	return entries
+}
+
+// Exists 原文件是否仍然存在
+func Exists(entry model.FileEntry) bool {
+	_, err := os.Stat(entry.Path)
+	return err == nil
+}
+
+// Missing 返回原文件已不存在的条目数，以及其中可从快照恢复的数量
+func Missing(item *model.ClipboardItem) (missing, recoverable int) {
+	for _, entry := range Entries(item) {
+		if Exists(entry) {
+			continue
+		}
+		missing++
+		if entry.Snapshot != "" {
+			if _, err := os.Stat(entry.Snapshot); err == nil {
+				recoverable++
+			}
+		}
+	}
+	return missing, recoverable
+}
+
+// Resolve 返回粘贴时使用的路径：原文件存在时用原路径，否则使用快照
+// 原文件与快照都不存在的条目仍返回原路径
+func Resolve(item *model.ClipboardItem) []string {
+	var paths []string
+	for _, entry := range Entries(item) {
+		path := entry.Path
+		if !Exists(entry) && entry.Snapshot != "" {
+			if _, err := os.Stat(entry.Snapshot); err == nil {
+				path = entry.Snapshot
+			}
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// hashFile 计算文件的 SHA-256
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// copyFile 复制文件内容
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	return out.Close()
+}
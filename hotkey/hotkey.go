@@ -0,0 +1,264 @@
+// Package hotkey 解析快捷键字符串（如 "Ctrl+Shift+V"）并注册全局快捷键。
+//
+// 全局快捷键目前只支持 Linux/X11，其他平台或注册失败时由调用方退回为窗口内快捷键。
+package hotkey
+
+import (
+	"errors"
+	"fmt"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"strings"
+	"sync"
+)
+
+// 注册错误
+var (
+	ErrUnsupported = errors.New("当前平台不支持全局快捷键")
+	ErrConflict    = errors.New("快捷键已被其他程序占用")
+)
+
+// Combo 按键组合
+type Combo struct {
+	Modifier fyne.KeyModifier
+	Key      fyne.KeyName
+}
+
+// modifierNames 修饰键的显示名称，按显示顺序排列
+var modifierNames = []struct {
+	modifier fyne.KeyModifier
+	name     string
+}{
+	{fyne.KeyModifierControl, "Ctrl"},
+	{fyne.KeyModifierAlt, "Alt"},
+	{fyne.KeyModifierShift, "Shift"},
+	{fyne.KeyModifierSuper, "Super"},
+}
+
+// modifierAliases 解析时接受的修饰键名称（小写）
+var modifierAliases = map[string]fyne.KeyModifier{
+	"ctrl":    fyne.KeyModifierControl,
+	"control": fyne.KeyModifierControl,
+	"alt":     fyne.KeyModifierAlt,
+	"option":  fyne.KeyModifierAlt,
+	"shift":   fyne.KeyModifierShift,
+	"super":   fyne.KeyModifierSuper,
+	"win":     fyne.KeyModifierSuper,
+	"meta":    fyne.KeyModifierSuper,
+	"cmd":     fyne.KeyModifierSuper,
+}
+
+// namedKeys 字母、数字、功能键以外可用的按键及其显示名称
+var namedKeys = map[fyne.KeyName]string{
+	fyne.KeySpace:     "Space",
+	fyne.KeyReturn:    "Enter",
+	fyne.KeyTab:       "Tab",
+	fyne.KeyEscape:    "Esc",
+	fyne.KeyBackspace: "Backspace",
+	fyne.KeyInsert:    "Insert",
+	fyne.KeyDelete:    "Delete",
+	fyne.KeyHome:      "Home",
+	fyne.KeyEnd:       "End",
+	fyne.KeyPageUp:    "PageUp",
+	fyne.KeyPageDown:  "PageDown",
+	fyne.KeyUp:        "Up",
+	fyne.KeyDown:      "Down",
+	fyne.KeyLeft:      "Left",
+	fyne.KeyRight:     "Right",
+	fyne.KeyComma:     "Comma",
+}
+
+// keyAliases 解析时额外接受的按键名称（小写）
+var keyAliases = map[string]fyne.KeyName{
+	"return":   fyne.KeyReturn,
+	"escape":   fyne.KeyEscape,
+	"del":      fyne.KeyDelete,
+	"ins":      fyne.KeyInsert,
+	"pgup":     fyne.KeyPageUp,
+	"pgdn":     fyne.KeyPageDown,
+	"pagedown": fyne.KeyPageDown,
+	"pageup":   fyne.KeyPageUp,
+	",":        fyne.KeyComma,
+}
+
+// Parse 解析快捷键字符串，修饰键与按键之间用 "+" 分隔，不区分大小写
+func Parse(s string) (Combo, error) {
+	parts := strings.Split(s, "+")
+	var combo Combo
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if i < len(parts)-1 {
+			modifier, ok := modifierAliases[strings.ToLower(part)]
+			if !ok {
+				return Combo{}, fmt.Errorf("无效的修饰键 %q", part)
+			}
+			combo.Modifier |= modifier
+			continue
+		}
+
+		key, ok := parseKey(part)
+		if !ok {
+			return Combo{}, fmt.Errorf("无效的按键 %q", part)
+		}
+		combo.Key = key
+	}
+	if err := combo.Validate(); err != nil {
+		return Combo{}, err
+	}
+	return combo, nil
+}
+
+// parseKey 解析单个按键名称
+func parseKey(s string) (fyne.KeyName, bool) {
+	lower := strings.ToLower(s)
+	if key, ok := keyAliases[lower]; ok {
+		return key, true
+	}
+	if len(s) == 1 {
+		c := strings.ToUpper(s)[0]
+		if c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' {
+			return fyne.KeyName(c), true
+		}
+		return "", false
+	}
+	for key, name := range namedKeys {
+		if strings.ToLower(name) == lower {
+			return key, true
+		}
+	}
+	if isFunctionKey(fyne.KeyName(strings.ToUpper(s))) {
+		return fyne.KeyName(strings.ToUpper(s)), true
+	}
+	return "", false
+}
+
+// isFunctionKey 是否为 F1-F12
+func isFunctionKey(key fyne.KeyName) bool {
+	for i := 1; i <= 12; i++ {
+		if key == fyne.KeyName(fmt.Sprintf("F%d", i)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate 检查组合是否可用作快捷键：必须包含 Ctrl、Alt 或 Super，
+// 只按 Shift 的组合会和正常输入冲突
+func (c Combo) Validate() error {
+	if c.Key == "" {
+		return errors.New("缺少按键")
+	}
+	if c.Modifier&(fyne.KeyModifierControl|fyne.KeyModifierAlt|fyne.KeyModifierSuper) == 0 {
+		return errors.New("快捷键必须包含 Ctrl、Alt 或 Super")
+	}
+	if _, ok := parseKey(c.displayKey()); !ok {
+		return fmt.Errorf("不支持的按键 %q", c.Key)
+	}
+	return nil
+}
+
+// IsZero 是否为空组合（未设置快捷键）
+func (c Combo) IsZero() bool {
+	return c.Key == ""
+}
+
+// String 返回规范的显示形式，如 "Ctrl+Shift+V"，可被 Parse 解析
+func (c Combo) String() string {
+	if c.IsZero() {
+		return ""
+	}
+	var parts []string
+	for _, m := range modifierNames {
+		if c.Modifier&m.modifier != 0 {
+			parts = append(parts, m.name)
+		}
+	}
+	return strings.Join(append(parts, c.displayKey()), "+")
+}
+
+// displayKey 按键的显示名称
+func (c Combo) displayKey() string {
+	if name, ok := namedKeys[c.Key]; ok {
+		return name
+	}
+	return string(c.Key)
+}
+
+// Shortcut 转换为窗口内快捷键
+func (c Combo) Shortcut() *desktop.CustomShortcut {
+	return &desktop.CustomShortcut{KeyName: c.Key, Modifier: c.Modifier}
+}
+
+// FromShortcut 将窗口收到的快捷键转换为组合，复制、撤销等标准快捷键按其按键转换
+func FromShortcut(shortcut fyne.Shortcut) (Combo, bool) {
+	ks, ok := shortcut.(fyne.KeyboardShortcut)
+	if !ok {
+		return Combo{}, false
+	}
+	return Combo{Modifier: ks.Mod(), Key: ks.Key()}, true
+}
+
+// Hotkey 已注册的全局快捷键
+type Hotkey struct {
+	combo    Combo
+	keydown  chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// Register 注册全局快捷键，平台不支持时返回 ErrUnsupported，被其他程序占用时返回 ErrConflict
+func Register(combo Combo) (*Hotkey, error) {
+	if err := combo.Validate(); err != nil {
+		return nil, err
+	}
+	h := &Hotkey{
+		combo:   combo,
+		keydown: make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	if err := h.start(); err != nil {
+		return nil, fmt.Errorf("注册全局快捷键 %s 失败: %w", combo, err)
+	}
+	return h, nil
+}
+
+// Available 检查组合当前能否注册为全局快捷键
+func Available(combo Combo) error {
+	h, err := Register(combo)
+	if err != nil {
+		return err
+	}
+	h.Unregister()
+	return nil
+}
+
+// Combo 返回注册的按键组合
+func (h *Hotkey) Combo() Combo {
+	return h.combo
+}
+
+// Keydown 快捷键按下时收到通知，注销后通道关闭（处理不及时的连按会被合并）
+func (h *Hotkey) Keydown() <-chan struct{} {
+	return h.keydown
+}
+
+// Unregister 注销快捷键，可重复调用
+func (h *Hotkey) Unregister() {
+	if h == nil {
+		return
+	}
+	h.stopOnce.Do(func() {
+		close(h.stop)
+	})
+	<-h.done
+}
+
+// notify 发出按下通知，上一次还未处理时丢弃
+func (h *Hotkey) notify() {
+	select {
+	case h.keydown <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,236 @@
+//go:build linux && cgo
+
+package hotkey
+
+/*
+#cgo LDFLAGS: -ldl
+
+#include <dlfcn.h>
+#include <errno.h>
+#include <poll.h>
+#include <stdlib.h>
+#include <X11/Xlib.h>
+
+// 与 golang.design/x/clipboard 一样在运行时加载 libX11，没有 X11 时不影响启动
+static void *libX11;
+static Display *(*pXOpenDisplay)(const char *);
+static int (*pXCloseDisplay)(Display *);
+static Window (*pXDefaultRootWindow)(Display *);
+static KeySym (*pXStringToKeysym)(const char *);
+static KeyCode (*pXKeysymToKeycode)(Display *, KeySym);
+static int (*pXGrabKey)(Display *, int, unsigned int, Window, Bool, int, int);
+static int (*pXUngrabKey)(Display *, int, unsigned int, Window);
+static int (*pXSync)(Display *, Bool);
+static int (*pXPending)(Display *);
+static int (*pXNextEvent)(Display *, XEvent *);
+static int (*pXConnectionNumber)(Display *);
+static XErrorHandler (*pXSetErrorHandler)(XErrorHandler);
+
+static int loadX11(void) {
+	if (libX11) {
+		return 1;
+	}
+	libX11 = dlopen("libX11.so.6", RTLD_LAZY);
+	if (!libX11) {
+		libX11 = dlopen("libX11.so", RTLD_LAZY);
+	}
+	if (!libX11) {
+		return 0;
+	}
+	pXOpenDisplay = dlsym(libX11, "XOpenDisplay");
+	pXCloseDisplay = dlsym(libX11, "XCloseDisplay");
+	pXDefaultRootWindow = dlsym(libX11, "XDefaultRootWindow");
+	pXStringToKeysym = dlsym(libX11, "XStringToKeysym");
+	pXKeysymToKeycode = dlsym(libX11, "XKeysymToKeycode");
+	pXGrabKey = dlsym(libX11, "XGrabKey");
+	pXUngrabKey = dlsym(libX11, "XUngrabKey");
+	pXSync = dlsym(libX11, "XSync");
+	pXPending = dlsym(libX11, "XPending");
+	pXNextEvent = dlsym(libX11, "XNextEvent");
+	pXConnectionNumber = dlsym(libX11, "XConnectionNumber");
+	pXSetErrorHandler = dlsym(libX11, "XSetErrorHandler");
+	return 1;
+}
+
+// 锁定键（CapsLock、NumLock）开启时按键的修饰位不同，需要分别注册
+static const unsigned int lockMasks[] = {0, LockMask, Mod2Mask, LockMask | Mod2Mask};
+
+static Display *grabDisplay;
+static int grabFailed;
+static XErrorHandler prevHandler;
+
+// 只记录本连接的错误（另一程序已注册时 XGrabKey 返回 BadAccess），其他连接交给原处理函数
+static int onError(Display *d, XErrorEvent *e) {
+	if (d == grabDisplay) {
+		grabFailed = 1;
+		return 0;
+	}
+	return prevHandler ? prevHandler(d, e) : 0;
+}
+
+static Display *openDisplay(void) {
+	if (!loadX11()) {
+		return NULL;
+	}
+	return pXOpenDisplay(NULL);
+}
+
+static void closeDisplay(Display *d) {
+	pXCloseDisplay(d);
+}
+
+// grabKey 在根窗口上注册快捷键，成功返回 1，按键无效返回 -1，被占用返回 0
+static int grabKey(Display *d, const char *keysym, unsigned int mods) {
+	KeySym sym = pXStringToKeysym(keysym);
+	if (sym == NoSymbol) {
+		return -1;
+	}
+	KeyCode code = pXKeysymToKeycode(d, sym);
+	if (code == 0) {
+		return -1;
+	}
+
+	Window root = pXDefaultRootWindow(d);
+	grabDisplay = d;
+	grabFailed = 0;
+	prevHandler = pXSetErrorHandler(onError);
+	for (int i = 0; i < 4; i++) {
+		pXGrabKey(d, code, mods | lockMasks[i], root, False, GrabModeAsync, GrabModeAsync);
+	}
+	pXSync(d, False);
+	if (grabFailed) {
+		for (int i = 0; i < 4; i++) {
+			pXUngrabKey(d, code, mods | lockMasks[i], root);
+		}
+		pXSync(d, False);
+	}
+	pXSetErrorHandler(prevHandler);
+	grabDisplay = NULL;
+	return grabFailed ? 0 : 1;
+}
+
+// waitKey 等待按键事件，按下返回 1，超时返回 0，连接断开返回 -1
+static int waitKey(Display *d, int timeoutMs) {
+	while (pXPending(d) > 0) {
+		XEvent ev;
+		pXNextEvent(d, &ev);
+		if (ev.type == KeyPress) {
+			return 1;
+		}
+	}
+
+	struct pollfd pfd = {pXConnectionNumber(d), POLLIN, 0};
+	int n = poll(&pfd, 1, timeoutMs);
+	if (n < 0) {
+		return errno == EINTR ? 0 : -1;
+	}
+	if (n > 0 && (pfd.revents & (POLLERR | POLLHUP))) {
+		return -1;
+	}
+	return 0;
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"runtime"
+	"strings"
+	"unsafe"
+)
+
+// pollTimeout 等待按键的超时（毫秒），决定注销的响应时间
+const pollTimeout = 200
+
+// x11Keysyms 与 X11 keysym 名称不同的按键
+var x11Keysyms = map[string]string{
+	"Space":     "space",
+	"Enter":     "Return",
+	"Esc":       "Escape",
+	"Backspace": "BackSpace",
+	"PageUp":    "Prior",
+	"PageDown":  "Next",
+	"Comma":     "comma",
+}
+
+// x11Modifiers 修饰键对应的 X11 修饰位
+var x11Modifiers = map[string]C.uint{
+	"Ctrl":  C.ControlMask,
+	"Alt":   C.Mod1Mask,
+	"Shift": C.ShiftMask,
+	"Super": C.Mod4Mask,
+}
+
+// start 在独立的 X11 连接上注册快捷键并开始等待按键
+func (h *Hotkey) start() error {
+	errc := make(chan error, 1)
+	go h.run(errc)
+	return <-errc
+}
+
+// run X11 连接只在本协程使用，协程固定在一个系统线程上
+func (h *Hotkey) run(errc chan<- error) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	defer close(h.done)
+	defer close(h.keydown)
+
+	display := C.openDisplay()
+	if display == nil {
+		errc <- fmt.Errorf("%w: 无法连接 X11", ErrUnsupported)
+		return
+	}
+	defer C.closeDisplay(display)
+
+	keysym, mods := x11Combo(h.combo)
+	ckeysym := C.CString(keysym)
+	defer C.free(unsafe.Pointer(ckeysym))
+	switch C.grabKey(display, ckeysym, mods) {
+	case 1:
+		errc <- nil
+	case 0:
+		errc <- ErrConflict
+		return
+	default:
+		errc <- errors.New("键盘布局中没有该按键")
+		return
+	}
+
+	for {
+		select {
+		case <-h.stop:
+			return // 关闭连接时 X11 自动取消注册
+		default:
+		}
+		switch C.waitKey(display, pollTimeout) {
+		case 1:
+			h.notify()
+		case -1:
+			log.Printf("全局快捷键 %s 的 X11 连接已断开", h.combo)
+			<-h.stop
+			return
+		}
+	}
+}
+
+// x11Combo 转换为 X11 keysym 名称与修饰位
+func x11Combo(combo Combo) (string, C.uint) {
+	var mods C.uint
+	for _, m := range modifierNames {
+		if combo.Modifier&m.modifier != 0 {
+			mods |= x11Modifiers[m.name]
+		}
+	}
+
+	key := combo.displayKey()
+	if name, ok := x11Keysyms[key]; ok {
+		return name, mods
+	}
+	if len(key) == 1 {
+		// 字母键的 keysym 为小写
+		return strings.ToLower(key), mods
+	}
+	return key, mods
+}
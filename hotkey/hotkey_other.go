@@ -0,0 +1,10 @@
+//go:build !linux || !cgo
+
+package hotkey
+
+// start 非 Linux 平台（或未启用 cgo）不支持全局快捷键
+func (h *Hotkey) start() error {
+	close(h.done)
+	close(h.keydown)
+	return ErrUnsupported
+}
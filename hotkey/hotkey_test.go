@@ -0,0 +1,70 @@
+package hotkey
+
+import (
+	"fyne.io/fyne/v2"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Combo
+		wantStr string // 规范形式，为空表示应解析失败
+	}{
+		{"Ctrl+Alt+V", Combo{fyne.KeyModifierControl | fyne.KeyModifierAlt, fyne.KeyV}, "Ctrl+Alt+V"},
+		{"shift + ctrl + v", Combo{fyne.KeyModifierControl | fyne.KeyModifierShift, fyne.KeyV}, "Ctrl+Shift+V"},
+		{"Super+V", Combo{fyne.KeyModifierSuper, fyne.KeyV}, "Super+V"},
+		{"Win+Cmd+Meta+1", Combo{fyne.KeyModifierSuper, fyne.Key1}, "Super+1"},
+		{"Control+Option+F12", Combo{fyne.KeyModifierControl | fyne.KeyModifierAlt, fyne.KeyF12}, "Ctrl+Alt+F12"},
+		{"Ctrl+space", Combo{fyne.KeyModifierControl, fyne.KeySpace}, "Ctrl+Space"},
+		{"Ctrl+Return", Combo{fyne.KeyModifierControl, fyne.KeyReturn}, "Ctrl+Enter"},
+		{"Alt+Escape", Combo{fyne.KeyModifierAlt, fyne.KeyEscape}, "Alt+Esc"},
+		{"Ctrl+PgDn", Combo{fyne.KeyModifierControl, fyne.KeyPageDown}, "Ctrl+PageDown"},
+		{"Ctrl+,", Combo{fyne.KeyModifierControl, fyne.KeyComma}, "Ctrl+Comma"},
+		{"V", Combo{}, ""},          // 缺少修饰键
+		{"Shift+V", Combo{}, ""},    // 只有 Shift
+		{"Ctrl+", Combo{}, ""},      // 缺少按键
+		{"Hyper+V", Combo{}, ""},    // 未知修饰键
+		{"Ctrl+F13", Combo{}, ""},   // 不支持的功能键
+		{"Ctrl+VV", Combo{}, ""},    // 未知按键
+		{"Ctrl+/", Combo{}, ""},     // 不支持的符号
+		{"", Combo{}, ""},           // 空字符串
+		{"Ctrl+Alt", Combo{}, ""},   // 只有修饰键
+		{"Ctrl+V+Alt", Combo{}, ""}, // 按键不在最后
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			if tt.wantStr == "" {
+				if err == nil {
+					t.Fatalf("Parse(%q) = %v，期望出错", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) 出错: %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %+v，期望 %+v", tt.in, got, tt.want)
+			}
+			if got.String() != tt.wantStr {
+				t.Errorf("String() = %q，期望 %q", got.String(), tt.wantStr)
+			}
+			// 规范形式可以再次解析为同一组合
+			if again, err := Parse(got.String()); err != nil || again != got {
+				t.Errorf("Parse(%q) = %+v, %v，期望 %+v", got.String(), again, err, got)
+			}
+		})
+	}
+}
+
+func TestZeroCombo(t *testing.T) {
+	var c Combo
+	if !c.IsZero() || c.String() != "" {
+		t.Errorf("空组合 IsZero=%v String=%q", c.IsZero(), c.String())
+	}
+	if c.Validate() == nil {
+		t.Error("空组合不应通过校验")
+	}
+}
@@ -0,0 +1,287 @@
+package httpapi
+
+import (
+	"bytes"
+	"clipboard/classify"
+	"clipboard/model"
+	"clipboard/sensitive"
+	"clipboard/thumbnail"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxBodyBytes 请求体大小上限
+const maxBodyBytes = 10 << 20
+
+// errNotFound 项不存在
+var errNotFound = errors.New("未找到该项")
+
+// addRequest 添加文本项的请求体
+type addRequest struct {
+	Content  string   `json:"content"`
+	Tags     []string `json:"tags"`
+	Favorite bool     `json:"favorite"`
+}
+
+// updateRequest 修改项的请求体，未提供的字段保持不变
+type updateRequest struct {
+	Content *string   `json:"content"`
+	Tags    *[]string `json:"tags"`
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	items, err := s.store.Search(query.Get("q"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+
+	if !revealed(r) {
+		redacted := make([]*model.ClipboardItem, 0, len(items))
+		for _, item := range items {
+			redacted = append(redacted, item.Redacted())
+		}
+		items = redacted
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+func (s *Server) handleAdd(w http.ResponseWriter, r *http.Request) {
+	var req addRequest
+	if err := decodeBody(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if strings.TrimSpace(req.Content) == "" {
+		writeError(w, http.StatusBadRequest, errors.New("内容不能为空"))
+		return
+	}
+
+	item := model.NewClipboardItem(model.TypeText, req.Content, "")
+	item.Subtype, item.Language = classify.Text(req.Content)
+	item.IsFavorite = req.Favorite
+	for _, tag := range req.Tags {
+		item.AddTag(tag)
+	}
+	sensitive.Mark(item, s.sensitive)
+
+	if _, err := s.store.AddItem(item); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, item.Redacted())
+}
+
+func (s *Server) handleGet(w http.ResponseWriter, r *http.Request) {
+	item, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	if !revealed(r) {
+		item = item.Redacted()
+	}
+	writeJSON(w, http.StatusOK, item)
+}
+
+func (s *Server) handleUpdate(w http.ResponseWriter, r *http.Request) {
+	item, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	var req updateRequest
+	if err := decodeBody(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	edited := *item
+	if req.Content != nil {
+		if item.Type != model.TypeText && item.Type != model.TypeSnippet {
+			writeError(w, http.StatusBadRequest, errors.New("只有文本项可以修改内容"))
+			return
+		}
+		edited.Content = *req.Content
+		if edited.Type == model.TypeText {
+			edited.Subtype, edited.Language = classify.Text(edited.Content)
+		}
+	}
+	if req.Tags != nil {
+		edited.Tags = nil
+		for _, tag := range *req.Tags {
+			edited.AddTag(tag)
+		}
+	}
+
+	if _, err := s.store.UpdateItem(&edited); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, edited.Redacted())
+}
+
+func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request) {
+	item, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	if _, err := s.store.DeleteItem(item.ID); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleFavorite(w http.ResponseWriter, r *http.Request) {
+	item, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	items, err := s.store.ToggleFavorite(item.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	for _, updated := range items {
+		if updated.ID == item.ID {
+			writeJSON(w, http.StatusOK, updated.Redacted())
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, errNotFound)
+}
+
+func (s *Server) handleImage(w http.ResponseWriter, r *http.Request) {
+	item, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	if item.Type != model.TypeImage || item.ImagePath == "" {
+		writeError(w, http.StatusNotFound, errors.New("该项不是图片"))
+		return
+	}
+
+	path := item.ImagePath
+	if v := r.URL.Query().Get("thumb"); v == "1" || v == "true" {
+		thumb, err := thumbnail.Ensure(item.ImagePath)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		path = thumb
+	}
+	http.ServeFile(w, r, path)
+}
+
+// handleEvents 以 Server-Sent Events 推送新捕获的内容
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("不支持流式响应"))
+		return
+	}
+
+	ch := make(chan []byte, 16)
+	s.mu.Lock()
+	s.subscribers[ch] = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		if s.subscribers[ch] {
+			delete(s.subscribers, ch)
+			close(ch)
+		}
+		s.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+			w.Write(event)
+			flusher.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// lookup 按路径中的 ID 查找项，找不到时写出 404
+func (s *Server) lookup(w http.ResponseWriter, r *http.Request) (*model.ClipboardItem, bool) {
+	id := r.PathValue("id")
+	items, err := s.store.LoadItems()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return nil, false
+	}
+	for _, item := range items {
+		if item.ID == id {
+			return item, true
+		}
+	}
+	writeError(w, http.StatusNotFound, errNotFound)
+	return nil, false
+}
+
+// revealed 请求是否要求显示敏感内容
+func revealed(r *http.Request) bool {
+	v := r.URL.Query().Get("reveal")
+	return v == "1" || v == "true"
+}
+
+// decodeBody 解码 JSON 请求体
+func decodeBody(r *http.Request, v any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("请求体无效: %w", err)
+	}
+	return nil
+}
+
+// encodeEvent 编码一条 SSE 事件
+func encodeEvent(name string, v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "event: %s\ndata: %s\n\n", name, data)
+	return buf.Bytes(), nil
+}
+
+// writeJSON 写出 JSON 响应
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.Encode(v)
+}
+
+// writeError 写出错误响应 {"error": "..."}
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,191 @@
+// Package httpapi 提供本地回环 HTTP 接口，供编辑器插件、启动器和浏览器扩展查询与写入剪贴板历史。
+//
+// 所有请求都需要携带令牌（Authorization: Bearer <令牌>；只有事件流 /api/events 因 EventSource 无法设置请求头而接受 ?token=）。
+//
+//	GET    /api/items                 列出历史，?q= 搜索，?limit= 限制数量，?reveal=1 显示敏感内容
+//	POST   /api/items                 添加文本项 {"content": "...", "tags": [...], "favorite": false}
+//	GET    /api/items/{id}            获取项
+//	PATCH  /api/items/{id}            修改内容或标签 {"content": "...", "tags": [...]}
+//	DELETE /api/items/{id}            删除项
+//	POST   /api/items/{id}/favorite   切换收藏状态
+//	GET    /api/items/{id}/image      获取图片原图，?thumb=1 获取缩略图
+//	GET    /api/events                Server-Sent Events，捕获到新内容时推送 capture 事件
+package httpapi
+
+import (
+	"clipboard/config"
+	"clipboard/model"
+	"clipboard/storage"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 配置错误
+var (
+	ErrNoToken     = errors.New("未配置访问令牌")
+	ErrNotLoopback = errors.New("HTTP 接口只能监听回环地址")
+)
+
+// heartbeatInterval SSE 心跳间隔，避免连接被代理或浏览器判定为空闲
+const heartbeatInterval = 30 * time.Second
+
+// Server 本地 HTTP 接口
+type Server struct {
+	store       storage.Storage
+	sensitive   config.SensitiveConfig
+	token       string
+	httpServer  *http.Server
+	mu          sync.Mutex
+	subscribers map[chan []byte]bool
+	lastCapture string // 上次推送的项（ID 与时间戳），避免重复推送
+}
+
+// NewServer 按配置创建 HTTP 接口，地址必须为回环地址且令牌不能为空
+func NewServer(cfg *config.AppConfig, store storage.Storage) (*Server, error) {
+	api := cfg.HTTPAPI
+	if api.Token == "" {
+		return nil, ErrNoToken
+	}
+	host, _, err := net.SplitHostPort(api.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("无效的监听地址 %q: %w", api.Addr, err)
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return nil, fmt.Errorf("%w: %s", ErrNotLoopback, api.Addr)
+	}
+
+	s := &Server{
+		store:       store,
+		sensitive:   cfg.Sensitive,
+		token:       api.Token,
+		subscribers: make(map[chan []byte]bool),
+	}
+	s.httpServer = &http.Server{
+		Addr:              api.Addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s, nil
+}
+
+// Start 开始监听，端口被占用等错误会立即返回
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return fmt.Errorf("监听 %s 失败: %w", s.httpServer.Addr, err)
+	}
+	go func() {
+		if err := s.httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("HTTP 接口异常退出: %v", err)
+		}
+	}()
+	log.Printf("HTTP 接口已启动: http://%s/api", s.httpServer.Addr)
+	return nil
+}
+
+// Close 停止服务并断开所有事件订阅
+func (s *Server) Close() error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	for ch := range s.subscribers {
+		close(ch)
+		delete(s.subscribers, ch)
+	}
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	return s.httpServer.Shutdown(ctx)
+}
+
+// GenerateToken 生成随机访问令牌
+func GenerateToken() string {
+	buf := make([]byte, 24)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// Handler 返回路由（带令牌校验与跨域处理）
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/items", s.handleList)
+	mux.HandleFunc("POST /api/items", s.handleAdd)
+	mux.HandleFunc("GET /api/items/{id}", s.handleGet)
+	mux.HandleFunc("PATCH /api/items/{id}", s.handleUpdate)
+	mux.HandleFunc("DELETE /api/items/{id}", s.handleDelete)
+	mux.HandleFunc("POST /api/items/{id}/favorite", s.handleFavorite)
+	mux.HandleFunc("GET /api/items/{id}/image", s.handleImage)
+	mux.HandleFunc("GET /api/events", s.handleEvents)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 允许浏览器扩展跨域访问，身份仍由令牌校验
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PATCH, DELETE, OPTIONS")
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if !s.authorized(r) {
+			writeError(w, http.StatusUnauthorized, errors.New("令牌无效"))
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// authorized 校验请求携带的令牌
+func (s *Server) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	// 查询参数中的令牌会留在日志和浏览历史里，只有无法设置请求头的事件流接受
+	if !ok && r.Method == http.MethodGet && r.URL.Path == "/api/events" {
+		token = r.URL.Query().Get("token")
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+// Notify 历史变化时调用，最新一项是新捕获的内容时向订阅者推送 capture 事件
+func (s *Server) Notify(items []*model.ClipboardItem) {
+	if s == nil || len(items) == 0 {
+		return
+	}
+
+	var latest *model.ClipboardItem
+	for _, item := range items {
+		if latest == nil || item.Timestamp.After(latest.Timestamp) {
+			latest = item
+		}
+	}
+	key := latest.ID + "@" + latest.Timestamp.String()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if key == s.lastCapture {
+		return
+	}
+	s.lastCapture = key
+
+	event, err := encodeEvent("capture", latest.Redacted())
+	if err != nil {
+		log.Printf("编码事件失败: %v", err)
+		return
+	}
+	for ch := range s.subscribers {
+		select {
+		case ch <- event:
+		default: // 订阅者处理过慢时丢弃
+		}
+	}
+}
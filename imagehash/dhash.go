@@ -0,0 +1,74 @@
+package imagehash
+
+import (
+	"fmt"
+	"image"
+	"math/bits"
+	"strconv"
+)
+
+// 差值哈希的采样网格：每行 9 个点比较出 8 位，共 8 行 64 位
+const (
+	gridWidth  = 9
+	gridHeight = 8
+)
+
+// DHash 计算图片的差值哈希（dHash）
+// 图片缩小为 9x8 灰度后逐行比较相邻像素亮度，对重新编码、轻微压缩不敏感
+func DHash(img image.Image) uint64 {
+	var gray [gridHeight][gridWidth]float64
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	for gy := 0; gy < gridHeight; gy++ {
+		y0 := bounds.Min.Y + gy*h/gridHeight
+		y1 := max(bounds.Min.Y+(gy+1)*h/gridHeight, y0+1)
+		for gx := 0; gx < gridWidth; gx++ {
+			x0 := bounds.Min.X + gx*w/gridWidth
+			x1 := max(bounds.Min.X+(gx+1)*w/gridWidth, x0+1)
+
+			// 区域平均亮度（ITU-R BT.601）
+			var sum float64
+			var n int
+			for y := y0; y < y1; y += step(y1 - y0) {
+				for x := x0; x < x1; x += step(x1 - x0) {
+					r, g, b, _ := img.At(x, y).RGBA()
+					sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+					n++
+				}
+			}
+			gray[gy][gx] = sum / float64(n)
+		}
+	}
+
+	var hash uint64
+	for gy := 0; gy < gridHeight; gy++ {
+		for gx := 0; gx < gridWidth-1; gx++ {
+			hash <<= 1
+			if gray[gy][gx] > gray[gy][gx+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+// step 大区域按步长采样，限制每个区域最多约 16x16 个采样点
+func step(span int) int {
+	return max(span/16, 1)
+}
+
+// Distance 返回两个哈希的汉明距离（不同位数）
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// Format 将哈希格式化为 16 位十六进制字符串
+func Format(hash uint64) string {
+	return fmt.Sprintf("%016x", hash)
+}
+
+// Parse 解析 Format 生成的十六进制哈希
+func Parse(s string) (uint64, error) {
+	return strconv.ParseUint(s, 16, 64)
+}
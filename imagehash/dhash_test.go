@@ -0,0 +1,104 @@
+package imagehash
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// gradient 生成水平渐变的灰度图，decreasing 为 true 时左亮右暗
+func gradient(w, h int, decreasing bool) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := uint8(x * 255 / (w - 1))
+			if decreasing {
+				v = 255 - v
+			}
+			img.SetGray(x, y, color.Gray{Y: v})
+		}
+	}
+	return img
+}
+
+// blocks 生成左右两半亮度不同的图片，用于测试缩放后哈希是否稳定
+func blocks(w, h int) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := uint8(40)
+			if x < w/2 != (y < h/2) {
+				v = 220
+			}
+			img.SetGray(x, y, color.Gray{Y: v})
+		}
+	}
+	return img
+}
+
+func TestDHash(t *testing.T) {
+	tests := []struct {
+		name string
+		img  image.Image
+		want uint64
+	}{
+		{"纯色", image.NewGray(image.Rect(0, 0, 64, 64)), 0},
+		{"左暗右亮", gradient(90, 40, false), 0},
+		{"左亮右暗", gradient(90, 40, true), ^uint64(0)},
+		{"大图按步长采样", gradient(2000, 1000, true), ^uint64(0)},
+		{"坐标不从原点开始", gradient(90, 40, true).SubImage(image.Rect(9, 4, 90, 40)), ^uint64(0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DHash(tt.img); got != tt.want {
+				t.Errorf("DHash = %016x，期望 %016x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDHashSimilarImages(t *testing.T) {
+	original := blocks(400, 300)
+	// 缩放（如高分屏截图）不应明显改变哈希
+	scaled := blocks(200, 150)
+
+	if d := Distance(DHash(original), DHash(scaled)); d > 2 {
+		t.Errorf("缩放后的汉明距离为 %d，期望不超过 2", d)
+	}
+	if d := Distance(DHash(original), DHash(gradient(400, 300, true))); d <= 6 {
+		t.Errorf("不同图片的汉明距离为 %d，期望大于 6", d)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b uint64
+		want int
+	}{
+		{0, 0, 0},
+		{0, 1, 1},
+		{0xff, 0x0f, 4},
+		{0, ^uint64(0), 64},
+	}
+	for _, tt := range tests {
+		if got := Distance(tt.a, tt.b); got != tt.want {
+			t.Errorf("Distance(%x, %x) = %d，期望 %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestFormatParse(t *testing.T) {
+	for _, hash := range []uint64{0, 1, 0x0123456789abcdef, ^uint64(0)} {
+		s := Format(hash)
+		if len(s) != 16 {
+			t.Errorf("Format(%x) = %q，期望 16 位", hash, s)
+		}
+		got, err := Parse(s)
+		if err != nil || got != hash {
+			t.Errorf("Parse(%q) = %x, %v，期望 %x", s, got, err, hash)
+		}
+	}
+	if _, err := Parse("not hex"); err == nil {
+		t.Error("无效的哈希应返回错误")
+	}
+}
@@ -0,0 +1,98 @@
+package imagemeta
+
+import (
+	"bytes"
+	"clipboard/imagehash"
+	"clipboard/model"
+	"fmt"
+	"image"
+	"os"
+	"sort"
+
+	_ "golang.org/x/image/webp" // 支持读取 WebP 图片的元数据
+)
+
+// 主色调提取参数
+const (
+	colorSamples = 64 // 每个方向的采样点数
+	colorBits    = 4  // 每个通道保留的位数，用于合并相近颜色
+	maxColors    = 3  // 返回的主色调数量
+)
+
+// FromBytes 解码图片数据并提取元数据
+func FromBytes(data []byte) (model.ImageMeta, error) {
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return model.ImageMeta{}, fmt.Errorf("图片解码失败: %w", err)
+	}
+
+	bounds := img.Bounds()
+	return model.ImageMeta{
+		Width:     bounds.Dx(),
+		Height:    bounds.Dy(),
+		ByteSize:  int64(len(data)),
+		Format:    format,
+		ImageHash: imagehash.Format(imagehash.DHash(img)),
+		Colors:    DominantColors(img, maxColors),
+	}, nil
+}
+
+// FromFile 读取图片文件并提取元数据
+func FromFile(path string) (model.ImageMeta, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return model.ImageMeta{}, fmt.Errorf("读取图片失败: %w", err)
+	}
+	return FromBytes(data)
+}
+
+// DominantColors 提取图片的主色调（#rrggbb），按占比从高到低排列
+// 在均匀网格上采样，按每通道高 4 位分桶，取像素最多的桶的平均色
+func DominantColors(img image.Image, n int) []string {
+	type bucket struct {
+		r, g, b, count uint64
+	}
+	buckets := make(map[uint32]*bucket)
+
+	bounds := img.Bounds()
+	for sy := 0; sy < colorSamples; sy++ {
+		y := bounds.Min.Y + (2*sy+1)*bounds.Dy()/(2*colorSamples)
+		for sx := 0; sx < colorSamples; sx++ {
+			x := bounds.Min.X + (2*sx+1)*bounds.Dx()/(2*colorSamples)
+			r, g, b, a := img.At(x, y).RGBA()
+			if a < 0x8000 {
+				continue // 忽略透明像素
+			}
+			r, g, b = r>>8, g>>8, b>>8
+
+			shift := 8 - colorBits
+			key := (r>>shift)<<(2*colorBits) | (g>>shift)<<colorBits | b>>shift
+			bk := buckets[key]
+			if bk == nil {
+				bk = &bucket{}
+				buckets[key] = bk
+			}
+			bk.r += uint64(r)
+			bk.g += uint64(g)
+			bk.b += uint64(b)
+			bk.count++
+		}
+	}
+
+	sorted := make([]*bucket, 0, len(buckets))
+	for _, bk := range buckets {
+		sorted = append(sorted, bk)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].count != sorted[j].count {
+			return sorted[i].count > sorted[j].count
+		}
+		return sorted[i].r+sorted[i].g+sorted[i].b < sorted[j].r+sorted[j].g+sorted[j].b
+	})
+
+	var colors []string
+	for _, bk := range sorted[:min(n, len(sorted))] {
+		colors = append(colors, fmt.Sprintf("#%02x%02x%02x", bk.r/bk.count, bk.g/bk.count, bk.b/bk.count))
+	}
+	return colors
+}
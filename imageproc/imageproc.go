@@ -0,0 +1,152 @@
+package imageproc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // 仅支持解码 WebP
+)
+
+// 输出格式
+const (
+	FormatKeep = ""     // 保持原格式（无法编码的格式转为 PNG）
+	FormatPNG  = "png"  // PNG
+	FormatJPEG = "jpeg" // JPEG
+	FormatGIF  = "gif"  // GIF（单帧）
+)
+
+// DefaultQuality JPEG 默认压缩质量
+const DefaultQuality = 90
+
+// 压缩到目标大小时的下限
+const (
+	minQuality   = 40 // JPEG 质量下限，低于此值改为缩小尺寸
+	minDimension = 64 // 最短边下限，仍超出目标大小时放弃
+	shrinkFactor = 0.8
+)
+
+// ErrTargetTooSmall 无法压缩到目标大小
+var ErrTargetTooSmall = errors.New("无法将图片压缩到目标大小以内")
+
+// Options 图片写回剪贴板前的处理选项
+type Options struct {
+	Format        string `json:"format,omitempty"`        // 输出格式，见 Format* 常量
+	MaxDimension  int    `json:"maxDimension,omitempty"`  // 最长边不超过该像素数，0 表示不限制
+	TargetBytes   int64  `json:"targetBytes,omitempty"`   // 输出不超过该字节数，0 表示不限制
+	Quality       int    `json:"quality,omitempty"`       // JPEG 压缩质量（1-100），0 表示默认
+	StripMetadata bool   `json:"stripMetadata,omitempty"` // 去除 EXIF 等元数据（重新编码即可去除）
+}
+
+// IsZero 是否没有任何处理
+func (o Options) IsZero() bool {
+	return o == Options{}
+}
+
+// Process 按选项处理图片数据，返回处理后的数据和格式
+func Process(data []byte, opts Options) ([]byte, string, error) {
+	img, srcFormat, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("图片解码失败: %w", err)
+	}
+
+	format := opts.Format
+	if format == FormatKeep {
+		format = srcFormat
+	}
+	if format != FormatPNG && format != FormatJPEG && format != FormatGIF {
+		format = FormatPNG
+	}
+
+	if opts.MaxDimension > 0 {
+		img = Resize(img, opts.MaxDimension)
+	}
+
+	quality := opts.Quality
+	if quality <= 0 || quality > 100 {
+		quality = DefaultQuality
+	}
+
+	// 重新编码（同时去除元数据），超出目标大小时逐步压缩
+	for {
+		var buf bytes.Buffer
+		if err := Encode(&buf, img, format, quality); err != nil {
+			return nil, "", err
+		}
+		if opts.TargetBytes <= 0 || int64(buf.Len()) <= opts.TargetBytes {
+			return buf.Bytes(), format, nil
+		}
+
+		// 超出目标大小：JPEG 先降低质量，其余情况缩小尺寸
+		if format == FormatJPEG && quality > minQuality {
+			quality = max(quality-10, minQuality)
+			continue
+		}
+		bounds := img.Bounds()
+		if min(bounds.Dx(), bounds.Dy()) <= minDimension {
+			return nil, "", fmt.Errorf("%w（%d 字节）", ErrTargetTooSmall, opts.TargetBytes)
+		}
+		img = Resize(img, int(float64(max(bounds.Dx(), bounds.Dy()))*shrinkFactor))
+	}
+}
+
+// Encode 按格式编码图片
+func Encode(w io.Writer, img image.Image, format string, quality int) error {
+	switch format {
+	case FormatPNG:
+		if err := png.Encode(w, img); err != nil {
+			return fmt.Errorf("PNG编码失败: %w", err)
+		}
+	case FormatJPEG, "jpg":
+		if err := jpeg.Encode(w, img, &jpeg.Options{Quality: quality}); err != nil {
+			return fmt.Errorf("JPEG编码失败: %w", err)
+		}
+	case FormatGIF:
+		// 使用固定调色板并抖动，避免空调色板导致颜色丢失
+		bounds := img.Bounds()
+		paletted := image.NewPaletted(bounds, palette.Plan9)
+		draw.FloydSteinberg.Draw(paletted, bounds, img, bounds.Min)
+		if err := gif.Encode(w, paletted, nil); err != nil {
+			return fmt.Errorf("GIF编码失败: %w", err)
+		}
+	default:
+		return fmt.Errorf("不支持的输出格式: %s", format)
+	}
+	return nil
+}
+
+// Resize 按比例缩小图片，使最长边不超过 maxDimension
+func Resize(img image.Image, maxDimension int) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if maxDimension <= 0 || (w <= maxDimension && h <= maxDimension) {
+		return img
+	}
+
+	dw, dh := maxDimension, h*maxDimension/w
+	if h > w {
+		dw, dh = w*maxDimension/h, maxDimension
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, max(dw, 1), max(dh, 1)))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
+	return dst
+}
+
+// MIMEType 返回格式对应的 MIME 类型
+func MIMEType(format string) string {
+	switch format {
+	case FormatJPEG, "jpg":
+		return "image/jpeg"
+	case FormatGIF:
+		return "image/gif"
+	default:
+		return "image/png"
+	}
+}
@@ -0,0 +1,28 @@
+package instance
+
+import (
+	"clipboard/ipc"
+	"os"
+)
+
+// CaptureLock 本地捕获锁：同一时间只允许一个进程（后台服务、本地捕获的图形界面，
+// 或没有它们时直接读写存储的命令行）运行剪贴板监听器并写入存储
+type CaptureLock struct {
+	file *os.File
+}
+
+// LockCapture 获取本地捕获锁，已被其他进程持有时返回 ErrRunning
+func LockCapture() (*CaptureLock, error) {
+	f, err := lockFile(ipc.RuntimePath("capture.lock"), ipc.SocketPath())
+	if err != nil {
+		return nil, err
+	}
+	return &CaptureLock{file: f}, nil
+}
+
+// Release 释放锁，可对 nil 调用
+func (l *CaptureLock) Release() {
+	if l != nil {
+		unlockFile(l.file)
+	}
+}
@@ -0,0 +1,194 @@
+// Package instance 保证图形界面只运行一个实例：第一个实例持有锁文件并监听套接字，
+// 之后启动的进程把命令转发给它后退出；另外提供本地捕获锁，保证只有一个进程写入历史记录。
+package instance
+
+import (
+	"bufio"
+	"clipboard/ipc"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrRunning 已有实例在运行
+var ErrRunning = errors.New("已有实例在运行")
+
+// 转发重试：对方刚拿到锁、尚未开始监听时稍等片刻
+const (
+	sendAttempts = 10
+	sendInterval = 200 * time.Millisecond
+)
+
+// Action 转发给运行中实例的动作
+type Action string
+
+const (
+	ActionShow   Action = "show"   // 显示并聚焦窗口
+	ActionSearch Action = "search" // 显示窗口并搜索
+	ActionPaste  Action = "paste"  // 将指定项写入剪贴板
+	ActionPick   Action = "pick"   // 打开快速粘贴窗口
+)
+
+// Command 启动参数解析出的命令
+type Command struct {
+	Action Action `json:"action"`
+	Query  string `json:"query,omitempty"` // ActionSearch 的搜索词
+	ID     string `json:"id,omitempty"`    // ActionPaste 的项 ID
+}
+
+// reply 对转发命令的应答
+type reply struct {
+	Error string `json:"error,omitempty"`
+}
+
+// Instance 当前进程持有的单实例锁
+type Instance struct {
+	lock     *os.File
+	listener net.Listener
+	commands chan Command
+	handlers sync.WaitGroup // 正在处理的连接，全部结束后才关闭 commands
+}
+
+// Acquire 获取单实例锁并开始接收转发的命令，已有实例运行时返回 ErrRunning
+func Acquire() (*Instance, error) {
+	lock, err := lockFile(ipc.RuntimePath("lock"), ipc.RuntimePath("gui.sock"))
+	if err != nil {
+		return nil, err
+	}
+
+	// 持有锁说明之前的套接字文件是残留的
+	path := ipc.RuntimePath("gui.sock")
+	os.Remove(path)
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		unlockFile(lock)
+		return nil, fmt.Errorf("监听实例套接字失败: %w", err)
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		unlockFile(lock)
+		return nil, fmt.Errorf("设置套接字权限失败: %w", err)
+	}
+
+	inst := &Instance{
+		lock:     lock,
+		listener: listener,
+		commands: make(chan Command, 4),
+	}
+	go inst.serve()
+	return inst, nil
+}
+
+// Commands 其他进程转发来的命令
+func (i *Instance) Commands() <-chan Command {
+	return i.commands
+}
+
+// Release 停止接收命令并释放锁
+func (i *Instance) Release() {
+	i.listener.Close()
+	unlockFile(i.lock)
+}
+
+// serve 接收转发的命令，每个连接一条
+func (i *Instance) serve() {
+	for {
+		conn, err := i.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				log.Printf("接收实例命令失败: %v", err)
+			}
+			i.handlers.Wait()
+			close(i.commands)
+			return
+		}
+		i.handlers.Add(1)
+		go i.handle(conn)
+	}
+}
+
+// handle 读取一条命令并应答
+func (i *Instance) handle(conn net.Conn) {
+	defer i.handlers.Done()
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	var cmd Command
+	var resp reply
+	if err := json.NewDecoder(conn).Decode(&cmd); err != nil {
+		resp.Error = "无法解析命令: " + err.Error()
+	} else {
+		select {
+		case i.commands <- cmd:
+		default:
+			resp.Error = "实例忙，请稍后重试"
+		}
+	}
+	json.NewEncoder(conn).Encode(resp)
+}
+
+// Send 把命令转发给运行中的实例
+func Send(cmd Command) error {
+	path := ipc.RuntimePath("gui.sock")
+
+	var conn net.Conn
+	var err error
+	for attempt := 0; attempt < sendAttempts; attempt++ {
+		if conn, err = net.DialTimeout("unix", path, time.Second); err == nil {
+			break
+		}
+		time.Sleep(sendInterval)
+	}
+	if err != nil {
+		return fmt.Errorf("连接运行中的实例失败: %w", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	if err := json.NewEncoder(conn).Encode(cmd); err != nil {
+		return fmt.Errorf("发送命令失败: %w", err)
+	}
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		return fmt.Errorf("读取应答失败: %w", err)
+	}
+	var resp reply
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return fmt.Errorf("解析应答失败: %w", err)
+	}
+	if resp.Error != "" {
+		return errors.New(resp.Error)
+	}
+	return nil
+}
+
+// ParseArgs 解析图形界面的启动参数：
+//
+//	（无参数）        显示窗口
+//	--search <搜索词>  显示窗口并搜索
+//	--paste <id>      将指定项写入剪贴板
+//	--pick            打开快速粘贴窗口
+func ParseArgs(args []string) (Command, error) {
+	if len(args) == 0 {
+		return Command{Action: ActionShow}, nil
+	}
+	if len(args) == 1 && args[0] == "--pick" {
+		return Command{Action: ActionPick}, nil
+	}
+	if len(args) != 2 {
+		return Command{}, fmt.Errorf("无效的参数: %v", args)
+	}
+	switch args[0] {
+	case "--search", "-s":
+		return Command{Action: ActionSearch, Query: args[1]}, nil
+	case "--paste", "-p":
+		return Command{Action: ActionPaste, ID: args[1]}, nil
+	default:
+		return Command{}, fmt.Errorf("未知参数: %s", args[0])
+	}
+}
@@ -0,0 +1,80 @@
+package instance
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    Command
+		wantErr bool
+	}{
+		{"无参数显示窗口", nil, Command{Action: ActionShow}, false},
+		{"搜索", []string{"--search", "关键词"}, Command{Action: ActionSearch, Query: "关键词"}, false},
+		{"搜索简写", []string{"-s", "a b"}, Command{Action: ActionSearch, Query: "a b"}, false},
+		{"粘贴", []string{"--paste", "abc123"}, Command{Action: ActionPaste, ID: "abc123"}, false},
+		{"粘贴简写", []string{"-p", "abc"}, Command{Action: ActionPaste, ID: "abc"}, false},
+		{"快速粘贴", []string{"--pick"}, Command{Action: ActionPick}, false},
+		{"缺少搜索词", []string{"--search"}, Command{}, true},
+		{"多余参数", []string{"--paste", "a", "b"}, Command{}, true},
+		{"未知参数", []string{"--foo", "bar"}, Command{}, true},
+		{"快速粘贴不带值", []string{"--pick", "x"}, Command{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseArgs(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseArgs(%q) 错误 = %v，期望出错 %v", tt.args, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseArgs(%q) = %+v，期望 %+v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAcquireForwardRelease(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	inst, err := Acquire()
+	if err != nil {
+		t.Fatalf("获取单实例锁失败: %v", err)
+	}
+	if _, err := Acquire(); !errors.Is(err, ErrRunning) {
+		t.Fatalf("重复获取返回 %v，期望 ErrRunning", err)
+	}
+
+	want := Command{Action: ActionSearch, Query: "hello"}
+	if err := Send(want); err != nil {
+		t.Fatalf("转发命令失败: %v", err)
+	}
+	select {
+	case got := <-inst.Commands():
+		if got != want {
+			t.Errorf("收到 %+v，期望 %+v", got, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("没有收到转发的命令")
+	}
+
+	// 释放后命令通道关闭，锁可以再次获取
+	inst.Release()
+	select {
+	case _, ok := <-inst.Commands():
+		if ok {
+			t.Error("释放后不应再收到命令")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("释放后命令通道未关闭")
+	}
+	again, err := Acquire()
+	if err != nil {
+		t.Fatalf("释放后重新获取失败: %v", err)
+	}
+	again.Release()
+}
@@ -0,0 +1,31 @@
+//go:build !unix
+
+package instance
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"time"
+)
+
+// lockFile 非 Unix 平台没有 flock：以持有者监听的套接字 sockPath 能否连通判断是否已被持有
+func lockFile(path, sockPath string) (*os.File, error) {
+	if conn, err := net.DialTimeout("unix", sockPath, time.Second); err == nil {
+		conn.Close()
+		return nil, ErrRunning
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("打开锁文件失败: %w", err)
+	}
+	fmt.Fprintf(f, "%d\n", os.Getpid())
+	return f, nil
+}
+
+// unlockFile 关闭并删除锁文件
+func unlockFile(f *os.File) {
+	f.Close()
+	os.Remove(f.Name())
+}
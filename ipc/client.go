@@ -0,0 +1,172 @@
+package ipc
+
+import (
+	"clipboard/clipboard"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// ErrClosed 连接已断开
+var ErrClosed = errors.New("与后台服务的连接已断开")
+
+// dialTimeout 连接后台服务的超时时间
+const dialTimeout = time.Second
+
+// Client JSON-RPC 客户端，可被多个协程同时使用
+type Client struct {
+	conn      net.Conn
+	writeMu   sync.Mutex
+	enc       *json.Encoder
+	mu        sync.Mutex
+	nextID    int64
+	pending   map[int64]chan *message
+	events    chan ChangeEvent
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// Dial 连接后台服务，服务未运行时返回错误
+func Dial(path string) (*Client, error) {
+	conn, err := net.DialTimeout("unix", path, dialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("连接后台服务失败: %w", err)
+	}
+
+	c := &Client{
+		conn:    conn,
+		enc:     json.NewEncoder(conn),
+		pending: make(map[int64]chan *message),
+		events:  make(chan ChangeEvent, 10),
+		closed:  make(chan struct{}),
+	}
+	go c.readLoop()
+	return c, nil
+}
+
+// DialWait 在 timeout 内重试连接，用于对方已持有捕获锁、尚未开始监听的情况
+func DialWait(path string, timeout time.Duration) (*Client, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		c, err := Dial(path)
+		if err == nil || time.Now().After(deadline) {
+			return c, err
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
+// Call 调用方法并将结果解码到 result（可为 nil）
+func (c *Client) Call(method string, params, result any) error {
+	msg := &message{JSONRPC: "2.0", Method: method}
+	if params != nil {
+		raw, err := json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("编码参数失败: %w", err)
+		}
+		msg.Params = raw
+	}
+
+	reply := make(chan *message, 1)
+	c.mu.Lock()
+	c.nextID++
+	id := c.nextID
+	c.pending[id] = reply
+	c.mu.Unlock()
+	msg.ID = &id
+
+	c.writeMu.Lock()
+	err := c.enc.Encode(msg)
+	c.writeMu.Unlock()
+	if err != nil {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+		return fmt.Errorf("发送请求失败: %w", err)
+	}
+
+	select {
+	case resp := <-reply:
+		if resp.Error != nil {
+			if resp.Error.Code == CodeQueueEmpty {
+				return clipboard.ErrQueueEmpty
+			}
+			return resp.Error
+		}
+		if result == nil {
+			return nil
+		}
+		if err := json.Unmarshal(resp.Result, result); err != nil {
+			return fmt.Errorf("解析 %s 结果失败: %w", method, err)
+		}
+		return nil
+	case <-c.closed:
+		return ErrClosed
+	}
+}
+
+// Subscribe 订阅历史变化通知，返回的通道在连接断开时关闭（通道满时丢弃通知）
+func (c *Client) Subscribe() (<-chan ChangeEvent, error) {
+	if err := c.Call(MethodSubscribe, nil, nil); err != nil {
+		return nil, err
+	}
+	return c.events, nil
+}
+
+// Done 连接断开时关闭的通道
+func (c *Client) Done() <-chan struct{} {
+	return c.closed
+}
+
+// Close 断开连接
+func (c *Client) Close() error {
+	err := c.conn.Close()
+	c.shutdown()
+	return err
+}
+
+// shutdown 标记连接已断开
+func (c *Client) shutdown() {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+	})
+}
+
+// readLoop 读取响应与通知
+func (c *Client) readLoop() {
+	defer func() {
+		c.shutdown()
+		close(c.events)
+	}()
+
+	dec := json.NewDecoder(c.conn)
+	for {
+		var msg message
+		if err := dec.Decode(&msg); err != nil {
+			return
+		}
+
+		if msg.ID == nil {
+			if msg.Method == NotifyChanged {
+				var event ChangeEvent
+				json.Unmarshal(msg.Params, &event)
+				select {
+				case c.events <- event:
+				default:
+				}
+			}
+			continue
+		}
+
+		c.mu.Lock()
+		reply, ok := c.pending[*msg.ID]
+		delete(c.pending, *msg.ID)
+		c.mu.Unlock()
+		if ok {
+			reply <- &msg
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Package ipc 实现后台服务与客户端（图形界面、命令行）之间的本地通信。
+//
+// 协议为 JSON-RPC 2.0，每条消息是一个 JSON 对象，通过 Unix 域套接字按流传输。
+// 客户端调用 subscribe 后，服务端会在历史变化时推送 changed 通知。
+package ipc
+
+import (
+	"clipboard/clipboard"
+	"clipboard/model"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// 方法名
+const (
+	MethodList         = "list"
+	MethodSearch       = "search"
+	MethodGet          = "get"
+	MethodSetClipboard = "setClipboard"
+	MethodDelete       = "delete"
+	MethodFavorite     = "favorite"
+	MethodSubscribe    = "subscribe"
+
+	MethodAdd          = "add"
+	MethodUpdate       = "update"
+	MethodReplace      = "replace"
+	MethodSaveItems    = "saveItems"
+	MethodRevisions    = "revisions"
+	MethodRecordUse    = "recordUse"
+	MethodBumpSimilar  = "bumpSimilarImage"
+	MethodSetImageMeta = "setImageMeta"
+	MethodPurgeExpired = "purgeExpired"
+	MethodImagePath    = "imagePath"
+
+	MethodPause        = "pause"
+	MethodResume       = "resume"
+	MethodPauseState   = "pauseState"
+	MethodQueueStart   = "queue.start"
+	MethodQueueAdvance = "queue.advance"
+	MethodQueueClear   = "queue.clear"
+	MethodQueueState   = "queue.state"
+
+	MethodReload = "reload"
+)
+
+// NotifyChanged 历史记录变化的通知名
+const NotifyChanged = "changed"
+
+// 错误码（-32xxx 为 JSON-RPC 保留码）
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternal       = -32603
+	CodeQueueEmpty     = 1001 // 粘贴队列为空
+)
+
+// message 请求、响应与通知共用的消息结构
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *int64          `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error JSON-RPC 错误
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s（错误码 %d）", e.Message, e.Code)
+}
+
+// IDParams 按 ID 操作单个项的参数
+type IDParams struct {
+	ID string `json:"id"`
+}
+
+// ListParams list 方法参数，Limit 为 0 表示全部
+type ListParams struct {
+	Limit int `json:"limit,omitempty"`
+}
+
+// SearchParams search 方法参数
+type SearchParams struct {
+	Query string `json:"query"`
+}
+
+// SetClipboardParams setClipboard 方法参数，Item 为空时按 ID 从存储中查找
+type SetClipboardParams struct {
+	ID      string                 `json:"id,omitempty"`
+	Item    *model.ClipboardItem   `json:"item,omitempty"`
+	Options clipboard.PasteOptions `json:"options"`
+}
+
+// ItemParams 提交单个项的参数
+type ItemParams struct {
+	Item *model.ClipboardItem `json:"item"`
+}
+
+// ItemsParams 提交一组项的参数
+type ItemsParams struct {
+	Items []*model.ClipboardItem `json:"items"`
+}
+
+// ReplaceParams replace 方法参数
+type ReplaceParams struct {
+	RemoveIDs []string               `json:"removeIds"`
+	Items     []*model.ClipboardItem `json:"items"`
+}
+
+// BumpSimilarParams bumpSimilarImage 方法参数
+type BumpSimilarParams struct {
+	Hash        string    `json:"hash"`
+	MaxDistance int       `json:"maxDistance"`
+	At          time.Time `json:"at"`
+}
+
+// BumpSimilarResult bumpSimilarImage 方法结果
+type BumpSimilarResult struct {
+	Items []*model.ClipboardItem `json:"items"`
+	Found bool                   `json:"found"`
+}
+
+// ImageMetaParams setImageMeta 方法参数
+type ImageMetaParams struct {
+	Metas map[string]model.ImageMeta `json:"metas"`
+}
+
+// PauseParams pause 方法参数，Seconds 为 0 时直到手动恢复
+type PauseParams struct {
+	Seconds float64 `json:"seconds,omitempty"`
+}
+
+// PauseState pauseState 方法结果
+type PauseState struct {
+	Paused bool      `json:"paused"`
+	Until  time.Time `json:"until"`
+}
+
+// QueueStartParams queue.start 方法参数
+type QueueStartParams struct {
+	Items []*model.ClipboardItem `json:"items"`
+	Mode  clipboard.QueueMode    `json:"mode"`
+}
+
+// QueueState queue.state 方法结果
+type QueueState struct {
+	Current *model.ClipboardItem   `json:"current"`
+	Pending []*model.ClipboardItem `json:"pending"`
+}
+
+// ChangeEvent changed 通知参数
+type ChangeEvent struct {
+	Reason string `json:"reason"`
+}
+
+// SocketPath 后台服务的套接字路径
+func SocketPath() string {
+	return RuntimePath("sock")
+}
+
+// RuntimePath 当前用户的运行时文件路径（套接字、锁文件等），优先放在 XDG_RUNTIME_DIR 下，
+// suffix 为文件名后缀（如 "sock"、"lock"）
+func RuntimePath(suffix string) string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "clipboard-manager."+suffix)
+	}
+	return filepath.Join(os.TempDir(), "clipboard-manager-"+strconv.Itoa(os.Getuid())+"."+suffix)
+}
@@ -0,0 +1,210 @@
+package ipc
+
+import (
+	"clipboard/clipboard"
+	"clipboard/model"
+	"sync"
+	"time"
+)
+
+// RemoteStorage 通过后台服务读写历史记录，实现 storage.Storage
+type RemoteStorage struct {
+	client *Client
+}
+
+// NewRemoteStorage 创建远程存储
+func NewRemoteStorage(client *Client) *RemoteStorage {
+	return &RemoteStorage{client: client}
+}
+
+// itemsCall 调用返回历史列表的方法
+func (r *RemoteStorage) itemsCall(method string, params any) ([]*model.ClipboardItem, error) {
+	var items []*model.ClipboardItem
+	if err := r.client.Call(method, params, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *RemoteStorage) SaveItems(items []*model.ClipboardItem) error {
+	return r.client.Call(MethodSaveItems, ItemsParams{Items: items}, nil)
+}
+
+func (r *RemoteStorage) LoadItems() ([]*model.ClipboardItem, error) {
+	return r.itemsCall(MethodList, ListParams{})
+}
+
+func (r *RemoteStorage) AddItem(item *model.ClipboardItem) ([]*model.ClipboardItem, error) {
+	return r.itemsCall(MethodAdd, ItemParams{Item: item})
+}
+
+func (r *RemoteStorage) DeleteItem(id string) ([]*model.ClipboardItem, error) {
+	return r.itemsCall(MethodDelete, IDParams{ID: id})
+}
+
+func (r *RemoteStorage) ReplaceItems(removeIDs []string, newItems []*model.ClipboardItem) ([]*model.ClipboardItem, error) {
+	return r.itemsCall(MethodReplace, ReplaceParams{RemoveIDs: removeIDs, Items: newItems})
+}
+
+func (r *RemoteStorage) UpdateItem(item *model.ClipboardItem) ([]*model.ClipboardItem, error) {
+	return r.itemsCall(MethodUpdate, ItemParams{Item: item})
+}
+
+func (r *RemoteStorage) Revisions(id string) ([]model.Revision, error) {
+	var revisions []model.Revision
+	if err := r.client.Call(MethodRevisions, IDParams{ID: id}, &revisions); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (r *RemoteStorage) RecordUse(id string) error {
+	return r.client.Call(MethodRecordUse, IDParams{ID: id}, nil)
+}
+
+func (r *RemoteStorage) BumpSimilarImage(hash string, maxDistance int, at time.Time) ([]*model.ClipboardItem, bool, error) {
+	var result BumpSimilarResult
+	err := r.client.Call(MethodBumpSimilar, BumpSimilarParams{Hash: hash, MaxDistance: maxDistance, At: at}, &result)
+	return result.Items, result.Found, err
+}
+
+func (r *RemoteStorage) SetImageMeta(metas map[string]model.ImageMeta) error {
+	return r.client.Call(MethodSetImageMeta, ImageMetaParams{Metas: metas}, nil)
+}
+
+func (r *RemoteStorage) ToggleFavorite(id string) ([]*model.ClipboardItem, error) {
+	return r.itemsCall(MethodFavorite, IDParams{ID: id})
+}
+
+func (r *RemoteStorage) Search(keyword string) ([]*model.ClipboardItem, error) {
+	return r.itemsCall(MethodSearch, SearchParams{Query: keyword})
+}
+
+func (r *RemoteStorage) PurgeExpired() (int, error) {
+	var n int
+	err := r.client.Call(MethodPurgeExpired, nil, &n)
+	return n, err
+}
+
+// GetImagePath 返回后台服务的图片目录（与客户端在同一台机器上）
+func (r *RemoteStorage) GetImagePath() string {
+	var path string
+	r.client.Call(MethodImagePath, nil, &path)
+	return path
+}
+
+// Close 断开与后台服务的连接
+func (r *RemoteStorage) Close() error {
+	return r.client.Close()
+}
+
+// RemoteMonitor 由后台服务负责捕获时的监听器代理，方法与 clipboard.Monitor 一致
+type RemoteMonitor struct {
+	client     *Client
+	changeChan chan []*model.ClipboardItem
+	stopChan   chan struct{}
+	stopOnce   sync.Once
+}
+
+// NewRemoteMonitor 创建监听器代理
+func NewRemoteMonitor(client *Client) *RemoteMonitor {
+	return &RemoteMonitor{
+		client:     client,
+		changeChan: make(chan []*model.ClipboardItem, 10),
+		stopChan:   make(chan struct{}),
+	}
+}
+
+// Start 订阅后台服务的变化通知，连接断开时视为停止
+func (m *RemoteMonitor) Start() error {
+	events, err := m.client.Subscribe()
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		defer m.Stop()
+		for {
+			select {
+			case _, ok := <-events:
+				if !ok {
+					return
+				}
+				// 通知不携带历史，应用层收到后自行重新加载
+				select {
+				case m.changeChan <- nil:
+				default:
+				}
+			case <-m.stopChan:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop 停止转发通知（不影响后台服务的捕获）
+func (m *RemoteMonitor) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.stopChan)
+	})
+}
+
+// Done 停止时关闭的通道
+func (m *RemoteMonitor) Done() <-chan struct{} {
+	return m.stopChan
+}
+
+// ChangeChan 获取变化通知通道
+func (m *RemoteMonitor) ChangeChan() <-chan []*model.ClipboardItem {
+	return m.changeChan
+}
+
+// SetContentWith 由后台服务按选项写入剪贴板并记录使用次数
+func (m *RemoteMonitor) SetContentWith(item *model.ClipboardItem, opts clipboard.PasteOptions) error {
+	return m.client.Call(MethodSetClipboard, SetClipboardParams{Item: item, Options: opts}, nil)
+}
+
+// Pause 暂停后台服务的捕获，d 为 0 时直到手动恢复
+func (m *RemoteMonitor) Pause(d time.Duration) {
+	m.client.Call(MethodPause, PauseParams{Seconds: d.Seconds()}, nil)
+}
+
+// Resume 恢复后台服务的捕获
+func (m *RemoteMonitor) Resume() {
+	m.client.Call(MethodResume, nil, nil)
+}
+
+// PauseState 返回后台服务是否暂停捕获及截止时间
+func (m *RemoteMonitor) PauseState() (bool, time.Time) {
+	var state PauseState
+	m.client.Call(MethodPauseState, nil, &state)
+	return state.Paused, state.Until
+}
+
+// StartQueue 在后台服务中启动粘贴队列
+func (m *RemoteMonitor) StartQueue(items []*model.ClipboardItem, mode clipboard.QueueMode) error {
+	return m.client.Call(MethodQueueStart, QueueStartParams{Items: items, Mode: mode}, nil)
+}
+
+// AdvanceQueue 载入粘贴队列的下一项
+func (m *RemoteMonitor) AdvanceQueue() error {
+	return m.client.Call(MethodQueueAdvance, nil, nil)
+}
+
+// ClearQueue 结束粘贴队列
+func (m *RemoteMonitor) ClearQueue() {
+	m.client.Call(MethodQueueClear, nil, nil)
+}
+
+// QueueState 返回粘贴队列的当前项和等待项
+func (m *RemoteMonitor) QueueState() (*model.ClipboardItem, []*model.ClipboardItem) {
+	var state QueueState
+	m.client.Call(MethodQueueState, nil, &state)
+	return state.Current, state.Pending
+}
+
+// Reload 让后台服务重新读取配置
+func (m *RemoteMonitor) Reload() error {
+	return m.client.Call(MethodReload, nil, nil)
+}
@@ -0,0 +1,400 @@
+package ipc
+
+import (
+	"clipboard/clipboard"
+	"clipboard/model"
+	"clipboard/storage"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrAlreadyRunning 已有后台服务在监听套接字
+var ErrAlreadyRunning = errors.New("后台服务已在运行")
+
+// writeTimeout 单条消息的写入超时，超时的客户端被断开，避免不读取的订阅者阻塞服务
+const writeTimeout = 5 * time.Second
+
+// Backend 服务端依赖的存储与监听器，重新加载配置后二者可能被替换
+type Backend interface {
+	Storage() storage.Storage
+	Monitor() *clipboard.Monitor
+	Reload() error   // 重新读取配置并重建存储与监听器
+	SavePauseState() // 按配置持久化暂停状态
+}
+
+// Server JSON-RPC 服务端
+type Server struct {
+	backend  Backend
+	listener net.Listener
+	mu       sync.Mutex
+	conns    map[*serverConn]bool
+}
+
+// serverConn 一个客户端连接
+type serverConn struct {
+	net.Conn
+	writeMu    sync.Mutex
+	enc        *json.Encoder
+	subscribed bool
+}
+
+// handler 方法处理函数，mutating 为 true 时成功后通知订阅者
+type handler struct {
+	call     func(s *Server, params json.RawMessage) (any, error)
+	mutating bool
+}
+
+// handlers 所有方法（subscribe 需要连接信息，单独处理）
+var handlers = map[string]handler{
+	MethodList:         {withParams(handleList), false},
+	MethodSearch:       {withParams(handleSearch), false},
+	MethodGet:          {withParams(handleGet), false},
+	MethodSetClipboard: {withParams(handleSetClipboard), true},
+	MethodDelete:       {withParams(handleDelete), true},
+	MethodFavorite:     {withParams(handleFavorite), true},
+
+	MethodAdd:          {withParams(handleAdd), true},
+	MethodUpdate:       {withParams(handleUpdate), true},
+	MethodReplace:      {withParams(handleReplace), true},
+	MethodSaveItems:    {withParams(handleSaveItems), true},
+	MethodRevisions:    {withParams(handleRevisions), false},
+	MethodRecordUse:    {withParams(handleRecordUse), true},
+	MethodBumpSimilar:  {withParams(handleBumpSimilar), true},
+	MethodSetImageMeta: {withParams(handleSetImageMeta), true},
+	MethodPurgeExpired: {withParams(handlePurgeExpired), true},
+	MethodImagePath:    {withParams(handleImagePath), false},
+
+	MethodPause:        {withParams(handlePause), false},
+	MethodResume:       {withParams(handleResume), false},
+	MethodPauseState:   {withParams(handlePauseState), false},
+	MethodQueueStart:   {withParams(handleQueueStart), true},
+	MethodQueueAdvance: {withParams(handleQueueAdvance), true},
+	MethodQueueClear:   {withParams(handleQueueClear), false},
+	MethodQueueState:   {withParams(handleQueueState), false},
+
+	MethodReload: {withParams(handleReload), true},
+}
+
+// Listen 在 path 上监听，已有服务在运行时返回 ErrAlreadyRunning，残留的套接字文件会被删除
+func Listen(path string, backend Backend) (*Server, error) {
+	if _, err := os.Stat(path); err == nil {
+		if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+			conn.Close()
+			return nil, ErrAlreadyRunning
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("删除残留套接字失败: %w", err)
+		}
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("监听套接字失败: %w", err)
+	}
+	// 仅允许当前用户连接
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("设置套接字权限失败: %w", err)
+	}
+
+	return &Server{
+		backend:  backend,
+		listener: listener,
+		conns:    make(map[*serverConn]bool),
+	}, nil
+}
+
+// Serve 接受连接直到 Close 被调用
+func (s *Server) Serve() error {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+
+		c := &serverConn{Conn: conn, enc: json.NewEncoder(conn)}
+		s.mu.Lock()
+		s.conns[c] = true
+		s.mu.Unlock()
+		go s.serveConn(c)
+	}
+}
+
+// Close 停止监听并断开所有连接
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.mu.Lock()
+	for c := range s.conns {
+		c.Close()
+	}
+	s.mu.Unlock()
+	return err
+}
+
+// Notify 向所有订阅者推送历史变化通知，不等待发送完成
+func (s *Server) Notify(reason string) {
+	params, _ := json.Marshal(ChangeEvent{Reason: reason})
+	msg := &message{JSONRPC: "2.0", Method: NotifyChanged, Params: params}
+
+	s.mu.Lock()
+	var subscribers []*serverConn
+	for c := range s.conns {
+		if c.subscribed {
+			subscribers = append(subscribers, c)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, c := range subscribers {
+		go c.send(msg)
+	}
+}
+
+// serveConn 依次处理一个连接上的请求
+func (s *Server) serveConn(c *serverConn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, c)
+		s.mu.Unlock()
+		c.Close()
+	}()
+
+	dec := json.NewDecoder(c)
+	for {
+		var req message
+		if err := dec.Decode(&req); err != nil {
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				c.send(&message{JSONRPC: "2.0", Error: &Error{Code: CodeParseError, Message: "无法解析请求"}})
+			}
+			return
+		}
+
+		result, err := s.dispatch(c, &req)
+		if req.ID == nil {
+			continue // 客户端发来的通知不需要响应
+		}
+		resp := &message{JSONRPC: "2.0", ID: req.ID}
+		if err != nil {
+			resp.Error = toError(err)
+		} else if resp.Result, err = json.Marshal(result); err != nil {
+			resp.Error = &Error{Code: CodeInternal, Message: "编码结果失败: " + err.Error()}
+			resp.Result = nil
+		}
+		c.send(resp)
+	}
+}
+
+// dispatch 调用方法，修改历史的方法成功后通知订阅者
+func (s *Server) dispatch(c *serverConn, req *message) (any, error) {
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return nil, &Error{Code: CodeInvalidRequest, Message: "无效的请求"}
+	}
+	if req.Method == MethodSubscribe {
+		s.mu.Lock()
+		c.subscribed = true
+		s.mu.Unlock()
+		return true, nil
+	}
+
+	h, ok := handlers[req.Method]
+	if !ok {
+		return nil, &Error{Code: CodeMethodNotFound, Message: "未知方法: " + req.Method}
+	}
+	result, err := h.call(s, req.Params)
+	if err == nil && h.mutating {
+		s.Notify(req.Method)
+	}
+	return result, err
+}
+
+// send 写出一条消息（多个协程可能同时推送通知）
+func (c *serverConn) send(msg *message) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if err := c.enc.Encode(msg); err != nil {
+		// 写入失败后消息可能只发出一部分，连接无法继续使用
+		log.Printf("发送消息失败，断开连接: %v", err)
+		c.Close()
+	}
+}
+
+// toError 将处理错误转换为 JSON-RPC 错误
+func toError(err error) *Error {
+	var rpcErr *Error
+	switch {
+	case errors.As(err, &rpcErr):
+		return rpcErr
+	case errors.Is(err, clipboard.ErrQueueEmpty):
+		return &Error{Code: CodeQueueEmpty, Message: err.Error()}
+	default:
+		return &Error{Code: CodeInternal, Message: err.Error()}
+	}
+}
+
+// withParams 解码参数后调用处理函数
+func withParams[P any](fn func(s *Server, p P) (any, error)) func(*Server, json.RawMessage) (any, error) {
+	return func(s *Server, raw json.RawMessage) (any, error) {
+		var p P
+		if len(raw) > 0 && string(raw) != "null" {
+			if err := json.Unmarshal(raw, &p); err != nil {
+				return nil, &Error{Code: CodeInvalidParams, Message: "参数无效: " + err.Error()}
+			}
+		}
+		return fn(s, p)
+	}
+}
+
+// none 无参数方法的参数类型
+type none struct{}
+
+func handleList(s *Server, p ListParams) (any, error) {
+	items, err := s.backend.Storage().LoadItems()
+	if err != nil {
+		return nil, err
+	}
+	if p.Limit > 0 && len(items) > p.Limit {
+		items = items[:p.Limit]
+	}
+	return items, nil
+}
+
+func handleSearch(s *Server, p SearchParams) (any, error) {
+	return s.backend.Storage().Search(p.Query)
+}
+
+func handleGet(s *Server, p IDParams) (any, error) {
+	return s.find(p.ID)
+}
+
+func handleSetClipboard(s *Server, p SetClipboardParams) (any, error) {
+	item := p.Item
+	if item == nil {
+		var err error
+		if item, err = s.find(p.ID); err != nil {
+			return nil, err
+		}
+	}
+	return nil, s.backend.Monitor().SetContentWith(item, p.Options)
+}
+
+func handleDelete(s *Server, p IDParams) (any, error) {
+	return s.backend.Storage().DeleteItem(p.ID)
+}
+
+func handleFavorite(s *Server, p IDParams) (any, error) {
+	return s.backend.Storage().ToggleFavorite(p.ID)
+}
+
+func handleAdd(s *Server, p ItemParams) (any, error) {
+	if p.Item == nil {
+		return nil, &Error{Code: CodeInvalidParams, Message: "缺少 item"}
+	}
+	return s.backend.Storage().AddItem(p.Item)
+}
+
+func handleUpdate(s *Server, p ItemParams) (any, error) {
+	if p.Item == nil {
+		return nil, &Error{Code: CodeInvalidParams, Message: "缺少 item"}
+	}
+	return s.backend.Storage().UpdateItem(p.Item)
+}
+
+func handleReplace(s *Server, p ReplaceParams) (any, error) {
+	return s.backend.Storage().ReplaceItems(p.RemoveIDs, p.Items)
+}
+
+func handleSaveItems(s *Server, p ItemsParams) (any, error) {
+	return nil, s.backend.Storage().SaveItems(p.Items)
+}
+
+func handleRevisions(s *Server, p IDParams) (any, error) {
+	return s.backend.Storage().Revisions(p.ID)
+}
+
+func handleRecordUse(s *Server, p IDParams) (any, error) {
+	return nil, s.backend.Storage().RecordUse(p.ID)
+}
+
+func handleBumpSimilar(s *Server, p BumpSimilarParams) (any, error) {
+	items, found, err := s.backend.Storage().BumpSimilarImage(p.Hash, p.MaxDistance, p.At)
+	if err != nil {
+		return nil, err
+	}
+	return BumpSimilarResult{Items: items, Found: found}, nil
+}
+
+func handleSetImageMeta(s *Server, p ImageMetaParams) (any, error) {
+	return nil, s.backend.Storage().SetImageMeta(p.Metas)
+}
+
+func handlePurgeExpired(s *Server, _ none) (any, error) {
+	return s.backend.Storage().PurgeExpired()
+}
+
+func handleImagePath(s *Server, _ none) (any, error) {
+	return s.backend.Storage().GetImagePath(), nil
+}
+
+func handlePause(s *Server, p PauseParams) (any, error) {
+	s.backend.Monitor().Pause(time.Duration(p.Seconds * float64(time.Second)))
+	s.backend.SavePauseState()
+	return nil, nil
+}
+
+func handleResume(s *Server, _ none) (any, error) {
+	s.backend.Monitor().Resume()
+	s.backend.SavePauseState()
+	return nil, nil
+}
+
+func handlePauseState(s *Server, _ none) (any, error) {
+	paused, until := s.backend.Monitor().PauseState()
+	return PauseState{Paused: paused, Until: until}, nil
+}
+
+func handleQueueStart(s *Server, p QueueStartParams) (any, error) {
+	return nil, s.backend.Monitor().StartQueue(p.Items, p.Mode)
+}
+
+func handleQueueAdvance(s *Server, _ none) (any, error) {
+	return nil, s.backend.Monitor().AdvanceQueue()
+}
+
+func handleQueueClear(s *Server, _ none) (any, error) {
+	s.backend.Monitor().ClearQueue()
+	return nil, nil
+}
+
+func handleQueueState(s *Server, _ none) (any, error) {
+	current, pending := s.backend.Monitor().QueueState()
+	return QueueState{Current: current, Pending: pending}, nil
+}
+
+func handleReload(s *Server, _ none) (any, error) {
+	return nil, s.backend.Reload()
+}
+
+// find 按 ID 查找项
+func (s *Server) find(id string) (*model.ClipboardItem, error) {
+	items, err := s.backend.Storage().LoadItems()
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if item.ID == id {
+			return item, nil
+		}
+	}
+	return nil, fmt.Errorf("未找到ID为 %s 的项", id)
+}
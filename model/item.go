@@ -1,9 +1,12 @@
 package model
 
 import (
+	"fmt"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 	"log"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -11,34 +14,216 @@ import (
 type ItemType int
 
 const (
-	TypeText  ItemType = iota // 文本类型
-	TypeImage                 // 图片类型
-	TypeFile                  // 文件类型
+	TypeText    ItemType = iota // 文本类型
+	TypeImage                   // 图片类型
+	TypeFile                    // 文件类型
+	TypeSnippet                 // 片段类型（含占位符的模板，不受最大数量限制）
 )
 
+// String 返回类型名称（text/image/file）
+func (t ItemType) String() string {
+	switch t {
+	case TypeText:
+		return "text"
+	case TypeImage:
+		return "image"
+	case TypeFile:
+		return "file"
+	case TypeSnippet:
+		return "snippet"
+	default:
+		return "unknown"
+	}
+}
+
+// TextSubtype 文本内容的细分类型
+type TextSubtype string
+
+const (
+	SubtypePlain TextSubtype = "plain" // 普通文本
+	SubtypeURL   TextSubtype = "url"   // 网址
+	SubtypeEmail TextSubtype = "email" // 邮箱地址
+	SubtypePhone TextSubtype = "phone" // 电话号码
+	SubtypePath  TextSubtype = "path"  // 文件路径
+	SubtypeColor TextSubtype = "color" // 十六进制/RGB 颜色
+	SubtypeJSON  TextSubtype = "json"  // JSON
+	SubtypeXML   TextSubtype = "xml"   // XML/HTML
+	SubtypeSQL   TextSubtype = "sql"   // SQL 语句
+	SubtypeShell TextSubtype = "shell" // Shell 命令
+	SubtypeCode  TextSubtype = "code"  // 源代码（语言见 Language）
+)
+
+// SelectionSource 定义内容来源的选区
+type SelectionSource string
+
+const (
+	SourceClipboard SelectionSource = "clipboard" // CLIPBOARD 选区（Ctrl+C）
+	SourcePrimary   SelectionSource = "primary"   // X11 PRIMARY 选区（鼠标选中/中键粘贴）
+)
+
+// ImageMeta 图片元数据，仅图片项有值
+type ImageMeta struct {
+	Width     int      `json:"width,omitempty"`
+	Height    int      `json:"height,omitempty"`
+	ByteSize  int64    `json:"byteSize,omitempty"`                      // 图片文件字节数
+	Format    string   `json:"format,omitempty" gorm:"size:16"`         // png、jpeg、gif 等
+	ImageHash string   `json:"imageHash,omitempty" gorm:"size:16"`      // 感知哈希（dHash 十六进制），用于相似图片去重
+	Colors    []string `json:"colors,omitempty" gorm:"serializer:json"` // 主色调 #rrggbb，按占比从高到低
+}
+
+// FileEntry 文件项中的单个文件
+type FileEntry struct {
+	Path     string    `json:"path"`
+	Size     int64     `json:"size"`
+	ModTime  time.Time `json:"modTime"`
+	Mode     uint32    `json:"mode"` // os.FileMode
+	IsDir    bool      `json:"isDir,omitempty"`
+	Hash     string    `json:"hash,omitempty"`     // SHA-256，过大的文件和目录为空
+	Snapshot string    `json:"snapshot,omitempty"` // 快照副本路径，未开启快照或文件过大时为空
+}
+
 // ClipboardItem 表示一个剪贴板历史项
 type ClipboardItem struct {
-	ID         string         `json:"id" gorm:"primaryKey"`
-	Type       ItemType       `json:"type"`
-	Content    string         `json:"content"`   // 文本内容或文件路径
-	ImagePath  string         `json:"imagePath"` // 图片临时文件路径
-	Timestamp  time.Time      `json:"timestamp"`
-	IsFavorite bool           `json:"isFavorite"`
-	CreatedAt  time.Time      `json:"-"`
-	UpdatedAt  time.Time      `json:"-"`
-	DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
+	ID         string          `json:"id" gorm:"primaryKey"`
+	Type       ItemType        `json:"type"`
+	Content    string          `json:"content"`   // 文本内容或文件路径
+	ImagePath  string          `json:"imagePath"` // 图片临时文件路径
+	ImageMeta                  // 图片尺寸、大小、格式、感知哈希与主色调
+	Files      []FileEntry     `json:"files,omitempty" gorm:"serializer:json"` // 文件项的文件列表
+	Source     SelectionSource `json:"source" gorm:"size:16"`                  // 来源选区，为空时视为 CLIPBOARD
+	Subtype    TextSubtype     `json:"subtype,omitempty" gorm:"size:16;index"`
+	Language   string          `json:"language,omitempty" gorm:"size:32"` // 源代码的语言猜测
+	Timestamp  time.Time       `json:"timestamp"`
+	IsFavorite bool            `json:"isFavorite"`
+	Sensitive  bool            `json:"sensitive"` // 是否检测为敏感内容（密码、令牌等）
+	Tags       []string        `json:"tags,omitempty" gorm:"serializer:json"`
+	ExpiresAt  *time.Time      `json:"expiresAt,omitempty" gorm:"index"`             // 过期时间，为空表示永久保存
+	PasteCount int             `json:"pasteCount"`                                   // 写回剪贴板的次数
+	LastUsedAt *time.Time      `json:"lastUsedAt,omitempty"`                         // 最后一次写回剪贴板的时间
+	Revisions  []Revision      `json:"revisions,omitempty" gorm:"foreignKey:ItemID"` // 编辑前的历史版本（JSON存储内嵌，MySQL独立成表）
+	CreatedAt  time.Time       `json:"createdAt"`
+	UpdatedAt  time.Time       `json:"updatedAt"`
+	DeletedAt  gorm.DeletedAt  `json:"-" gorm:"index"`
+}
+
+// Revision 历史项被编辑前的内容版本
+type Revision struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ItemID    string    `json:"-" gorm:"size:191;index"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"createdAt"` // 该版本被替换的时间
 }
 
 // NewClipboardItem 创建新的剪贴板历史项
 func NewClipboardItem(itemType ItemType, content, imagePath string) *ClipboardItem {
+	now := time.Now()
 	return &ClipboardItem{
 		ID:         generateID(),
 		Type:       itemType,
 		Content:    content,
 		ImagePath:  imagePath,
-		Timestamp:  time.Now(),
+		Source:     SourceClipboard,
+		Timestamp:  now,
 		IsFavorite: false,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// AddTag 添加标签（已存在时忽略）
+func (i *ClipboardItem) AddTag(tag string) {
+	for _, t := range i.Tags {
+		if t == tag {
+			return
+		}
+	}
+	i.Tags = append(i.Tags, tag)
+}
+
+// Exportable 判断项是否允许导出或同步（敏感内容不允许）
+func (i *ClipboardItem) Exportable() bool {
+	return !i.Sensitive
+}
+
+// FindByIDPrefix 按 ID 或唯一的 ID 前缀查找项
+func FindByIDPrefix(items []*ClipboardItem, id string) (*ClipboardItem, error) {
+	var matched *ClipboardItem
+	for _, item := range items {
+		if item.ID == id {
+			return item, nil
+		}
+		if strings.HasPrefix(item.ID, id) {
+			if matched != nil {
+				return nil, fmt.Errorf("id 前缀 %s 匹配多个项", id)
+			}
+			matched = item
+		}
+	}
+	if matched == nil {
+		return nil, fmt.Errorf("未找到ID为 %s 的项", id)
 	}
+	return matched, nil
+}
+
+// MaskedContent 敏感内容对外显示时的掩码
+const MaskedContent = "••••••••"
+
+// Redacted 返回隐藏敏感内容后的副本，非敏感项原样返回
+func (i *ClipboardItem) Redacted() *ClipboardItem {
+	if !i.Sensitive {
+		return i
+	}
+	copied := *i
+	copied.Content = MaskedContent
+	copied.Revisions = nil
+	return &copied
+}
+
+// IsExpired 判断项是否已过期
+func (i *ClipboardItem) IsExpired(now time.Time) bool {
+	return i.ExpiresAt != nil && !now.Before(*i.ExpiresAt)
+}
+
+// Frecency 综合使用频率和最近使用时间的得分，越高越常用
+// 得分 = (粘贴次数 + 1) / (1 + 距最近活动的天数)，最近活动取最后使用和复制时间中较晚者
+func (i *ClipboardItem) Frecency(now time.Time) float64 {
+	last := i.Timestamp
+	if i.LastUsedAt != nil && i.LastUsedAt.After(last) {
+		last = *i.LastUsedAt
+	}
+	days := now.Sub(last).Hours() / 24
+	if days < 0 {
+		days = 0
+	}
+	return float64(i.PasteCount+1) / (1 + days)
+}
+
+// SortMode 历史项排序方式
+type SortMode string
+
+const (
+	SortRecent   SortMode = "recent"   // 按复制时间，最新在前
+	SortFrequent SortMode = "frequent" // 按粘贴次数，最多在前
+	SortFrecency SortMode = "frecency" // 按综合得分，最高在前
+)
+
+// SortItems 按指定方式原地排序
+func SortItems(items []*ClipboardItem, mode SortMode) {
+	now := time.Now()
+	sort.SliceStable(items, func(a, b int) bool {
+		x, y := items[a], items[b]
+		switch mode {
+		case SortFrequent:
+			if x.PasteCount != y.PasteCount {
+				return x.PasteCount > y.PasteCount
+			}
+		case SortFrecency:
+			if fx, fy := x.Frecency(now), y.Frecency(now); fx != fy {
+				return fx > fy
+			}
+		}
+		return x.Timestamp.After(y.Timestamp)
+	})
 }
 
 // 生成唯一ID
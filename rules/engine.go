@@ -0,0 +1,194 @@
+package rules
+
+import (
+	"clipboard/config"
+	"clipboard/model"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Input 待求值的捕获内容
+type Input struct {
+	Type      model.ItemType // 内容类型
+	Text      string         // 文本内容或文件列表
+	ImageSize int64          // 图片字节数（仅图片）
+}
+
+// Match 命中的规则
+type Match struct {
+	Index int                // 规则在配置中的序号
+	Rule  config.CaptureRule // 规则内容
+}
+
+// String 返回便于展示的命中描述
+func (m Match) String() string {
+	name := m.Rule.Name
+	if name == "" {
+		name = fmt.Sprintf("规则 #%d", m.Index+1)
+	}
+	return fmt.Sprintf("%s → %s", name, describeAction(m.Rule))
+}
+
+// compiledRule 预编译正则后的规则
+type compiledRule struct {
+	index   int
+	rule    config.CaptureRule
+	include *regexp.Regexp
+	exclude *regexp.Regexp
+}
+
+// Engine 捕获规则引擎
+type Engine struct {
+	rules []compiledRule
+}
+
+// NewEngine 创建规则引擎
+// 正则无效的规则会被跳过，并在返回的错误中说明；即使有错误，返回的引擎仍可使用
+func NewEngine(rules []config.CaptureRule) (*Engine, error) {
+	e := &Engine{}
+	var errs []error
+
+	for i, r := range rules {
+		if !r.Enabled {
+			continue
+		}
+
+		c := compiledRule{index: i, rule: r}
+		var err error
+		if r.Include != "" {
+			if c.include, err = regexp.Compile(r.Include); err != nil {
+				errs = append(errs, fmt.Errorf("规则 #%d 包含正则无效: %w", i+1, err))
+				continue
+			}
+		}
+		if r.Exclude != "" {
+			if c.exclude, err = regexp.Compile(r.Exclude); err != nil {
+				errs = append(errs, fmt.Errorf("规则 #%d 排除正则无效: %w", i+1, err))
+				continue
+			}
+		}
+		if r.Action == config.RuleActionExpire && r.ExpireMinutes <= 0 {
+			errs = append(errs, fmt.Errorf("规则 #%d 过期时间必须大于0", i+1))
+			continue
+		}
+
+		e.rules = append(e.rules, c)
+	}
+
+	return e, errors.Join(errs...)
+}
+
+// Evaluate 按顺序求值所有规则，返回全部命中的规则
+// 命中 drop 规则后停止求值
+func (e *Engine) Evaluate(in Input) []Match {
+	if e == nil {
+		return nil
+	}
+
+	var matches []Match
+	for _, c := range e.rules {
+		if !c.matches(in) {
+			continue
+		}
+		matches = append(matches, Match{Index: c.index, Rule: c.rule})
+		if c.rule.Action == config.RuleActionDrop {
+			break
+		}
+	}
+	return matches
+}
+
+// Apply 将命中规则的动作应用到项上，返回是否保留该项
+func Apply(matches []Match, item *model.ClipboardItem) bool {
+	for _, m := range matches {
+		switch m.Rule.Action {
+		case config.RuleActionDrop:
+			return false
+		case config.RuleActionExpire:
+			expiresAt := item.Timestamp.Add(time.Duration(m.Rule.ExpireMinutes) * time.Minute)
+			if item.ExpiresAt == nil || expiresAt.Before(*item.ExpiresAt) {
+				item.ExpiresAt = &expiresAt
+			}
+		case config.RuleActionTag:
+			if m.Rule.Tag != "" {
+				item.AddTag(m.Rule.Tag)
+			}
+		case config.RuleActionFavorite:
+			item.IsFavorite = true
+		}
+	}
+	return true
+}
+
+// Test 规则测试工具：用给定规则求值一段样例内容，返回命中的规则
+func Test(rules []config.CaptureRule, in Input) ([]Match, error) {
+	e, err := NewEngine(rules)
+	return e.Evaluate(in), err
+}
+
+// matches 判断规则的所有条件是否满足
+func (c compiledRule) matches(in Input) bool {
+	r := c.rule
+
+	if len(r.Types) > 0 {
+		found := false
+		for _, t := range r.Types {
+			if t == in.Type.String() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	// 图片没有文本内容，文本类条件只对文本和文件生效
+	if in.Type != model.TypeImage {
+		if c.include != nil && !c.include.MatchString(in.Text) {
+			return false
+		}
+		if c.exclude != nil && c.exclude.MatchString(in.Text) {
+			return false
+		}
+
+		length := utf8.RuneCountInString(in.Text)
+		if r.MinLength > 0 && length < r.MinLength {
+			return false
+		}
+		if r.MaxLength > 0 && length > r.MaxLength {
+			return false
+		}
+		if r.WhitespaceOnly && strings.TrimSpace(in.Text) != "" {
+			return false
+		}
+	} else if c.include != nil || r.MinLength > 0 || r.MaxLength > 0 || r.WhitespaceOnly {
+		return false
+	}
+
+	if r.MaxImageSize > 0 && (in.Type != model.TypeImage || in.ImageSize <= r.MaxImageSize) {
+		return false
+	}
+
+	return true
+}
+
+// describeAction 返回动作的中文描述
+func describeAction(r config.CaptureRule) string {
+	switch r.Action {
+	case config.RuleActionDrop:
+		return "丢弃"
+	case config.RuleActionExpire:
+		return fmt.Sprintf("%d分钟后过期", r.ExpireMinutes)
+	case config.RuleActionTag:
+		return "添加标签 " + r.Tag
+	case config.RuleActionFavorite:
+		return "自动收藏"
+	default:
+		return "未知动作 " + string(r.Action)
+	}
+}
@@ -0,0 +1,158 @@
+package rules
+
+import (
+	"clipboard/config"
+	"clipboard/model"
+	"testing"
+	"time"
+)
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []config.CaptureRule
+		in    Input
+		want  []int // 命中规则的序号
+	}{
+		{
+			name:  "包含正则命中",
+			rules: []config.CaptureRule{{Enabled: true, Include: `^https?://`, Action: config.RuleActionTag, Tag: "url"}},
+			in:    Input{Type: model.TypeText, Text: "https://example.com"},
+			want:  []int{0},
+		},
+		{
+			name:  "包含正则未命中",
+			rules: []config.CaptureRule{{Enabled: true, Include: `^https?://`, Action: config.RuleActionTag, Tag: "url"}},
+			in:    Input{Type: model.TypeText, Text: "hello"},
+		},
+		{
+			name:  "排除正则优先",
+			rules: []config.CaptureRule{{Enabled: true, Include: `\d+`, Exclude: `secret`, Action: config.RuleActionDrop}},
+			in:    Input{Type: model.TypeText, Text: "secret 123"},
+		},
+		{
+			name:  "未启用的规则被跳过",
+			rules: []config.CaptureRule{{Enabled: false, Action: config.RuleActionDrop}},
+			in:    Input{Type: model.TypeText, Text: "x"},
+		},
+		{
+			name:  "类型不符",
+			rules: []config.CaptureRule{{Enabled: true, Types: []string{"file"}, Action: config.RuleActionDrop}},
+			in:    Input{Type: model.TypeText, Text: "x"},
+		},
+		{
+			name:  "长度按字符计算",
+			rules: []config.CaptureRule{{Enabled: true, MaxLength: 2, Action: config.RuleActionDrop}},
+			in:    Input{Type: model.TypeText, Text: "中文"},
+			want:  []int{0},
+		},
+		{
+			name:  "短于最小长度",
+			rules: []config.CaptureRule{{Enabled: true, MinLength: 3, Action: config.RuleActionDrop}},
+			in:    Input{Type: model.TypeText, Text: "ab"},
+		},
+		{
+			name:  "仅空白",
+			rules: []config.CaptureRule{{Enabled: true, WhitespaceOnly: true, Action: config.RuleActionDrop}},
+			in:    Input{Type: model.TypeText, Text: " \t\n"},
+			want:  []int{0},
+		},
+		{
+			name:  "图片不匹配文本条件",
+			rules: []config.CaptureRule{{Enabled: true, Include: `.`, Action: config.RuleActionDrop}},
+			in:    Input{Type: model.TypeImage, ImageSize: 10},
+		},
+		{
+			name:  "图片超过大小上限",
+			rules: []config.CaptureRule{{Enabled: true, MaxImageSize: 100, Action: config.RuleActionDrop}},
+			in:    Input{Type: model.TypeImage, ImageSize: 101},
+			want:  []int{0},
+		},
+		{
+			name:  "图片未超过大小上限",
+			rules: []config.CaptureRule{{Enabled: true, MaxImageSize: 100, Action: config.RuleActionDrop}},
+			in:    Input{Type: model.TypeImage, ImageSize: 100},
+		},
+		{
+			name: "多条规则依次命中",
+			rules: []config.CaptureRule{
+				{Enabled: true, Action: config.RuleActionTag, Tag: "a"},
+				{Enabled: true, Action: config.RuleActionFavorite},
+			},
+			in:   Input{Type: model.TypeText, Text: "x"},
+			want: []int{0, 1},
+		},
+		{
+			name: "命中丢弃后停止求值",
+			rules: []config.CaptureRule{
+				{Enabled: true, Action: config.RuleActionDrop},
+				{Enabled: true, Action: config.RuleActionFavorite},
+			},
+			in:   Input{Type: model.TypeText, Text: "x"},
+			want: []int{0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, err := Test(tt.rules, tt.in)
+			if err != nil {
+				t.Fatalf("规则无效: %v", err)
+			}
+			if len(matches) != len(tt.want) {
+				t.Fatalf("命中 %v，期望序号 %v", matches, tt.want)
+			}
+			for i, m := range matches {
+				if m.Index != tt.want[i] {
+					t.Errorf("第 %d 个命中为规则 #%d，期望 #%d", i, m.Index, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNewEngineInvalidRules(t *testing.T) {
+	rules := []config.CaptureRule{
+		{Enabled: true, Include: `(`, Action: config.RuleActionDrop},
+		{Enabled: true, Exclude: `[`, Action: config.RuleActionDrop},
+		{Enabled: true, Action: config.RuleActionExpire},
+		{Enabled: true, Action: config.RuleActionFavorite},
+	}
+	e, err := NewEngine(rules)
+	if err == nil {
+		t.Fatal("无效规则未返回错误")
+	}
+	// 有错误时引擎仍可使用有效的规则
+	matches := e.Evaluate(Input{Type: model.TypeText, Text: "x"})
+	if len(matches) != 1 || matches[0].Index != 3 {
+		t.Errorf("命中 %v，期望只命中规则 #3", matches)
+	}
+}
+
+func TestApply(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	matches := []Match{
+		{Rule: config.CaptureRule{Action: config.RuleActionExpire, ExpireMinutes: 30}},
+		{Rule: config.CaptureRule{Action: config.RuleActionExpire, ExpireMinutes: 10}},
+		{Rule: config.CaptureRule{Action: config.RuleActionTag, Tag: "work"}},
+		{Rule: config.CaptureRule{Action: config.RuleActionFavorite}},
+	}
+	item := &model.ClipboardItem{Timestamp: now}
+	if !Apply(matches, item) {
+		t.Fatal("未命中丢弃规则的项不应被丢弃")
+	}
+	// 多条过期规则取最早的过期时间
+	if want := now.Add(10 * time.Minute); item.ExpiresAt == nil || !item.ExpiresAt.Equal(want) {
+		t.Errorf("过期时间为 %v，期望 %v", item.ExpiresAt, want)
+	}
+	if len(item.Tags) != 1 || item.Tags[0] != "work" {
+		t.Errorf("标签为 %v，期望 [work]", item.Tags)
+	}
+	if !item.IsFavorite {
+		t.Error("未自动收藏")
+	}
+
+	if Apply([]Match{{Rule: config.CaptureRule{Action: config.RuleActionDrop}}}, &model.ClipboardItem{}) {
+		t.Error("命中丢弃规则的项应被丢弃")
+	}
+}
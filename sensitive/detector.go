@@ -0,0 +1,202 @@
+package sensitive
+
+import (
+	"clipboard/config"
+	"clipboard/model"
+	"log"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Kind 敏感内容类别
+type Kind string
+
+const (
+	KindPrivateKey Kind = "private_key" // PEM 私钥
+	KindAWSKey     Kind = "aws_key"     // AWS 访问密钥
+	KindGitHub     Kind = "github"      // GitHub 令牌
+	KindJWT        Kind = "jwt"         // JSON Web Token
+	KindCreditCard Kind = "credit_card" // 银行卡号（Luhn 校验）
+	KindPassword   Kind = "password"    // 疑似密码
+)
+
+// Detector 单个敏感内容检测器
+type Detector struct {
+	Kind  Kind
+	Match func(text string) bool
+}
+
+var (
+	privateKeyRe = regexp.MustCompile(`-----BEGIN ((RSA|EC|DSA|OPENSSH|ENCRYPTED|PGP) )?PRIVATE KEY( BLOCK)?-----`)
+	awsKeyRe     = regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)
+	awsSecretRe  = regexp.MustCompile(`(?i)aws_secret_access_key\s*[=:]\s*[A-Za-z0-9/+]{40}`)
+	githubRe     = regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)
+	jwtRe        = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}\b`)
+	cardRe       = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+)
+
+// DefaultDetectors 默认检测器，按顺序执行
+var DefaultDetectors = []Detector{
+	{Kind: KindPrivateKey, Match: privateKeyRe.MatchString},
+	{Kind: KindAWSKey, Match: func(text string) bool {
+		return awsKeyRe.MatchString(text) || awsSecretRe.MatchString(text)
+	}},
+	{Kind: KindGitHub, Match: githubRe.MatchString},
+	{Kind: KindJWT, Match: jwtRe.MatchString},
+	{Kind: KindCreditCard, Match: containsCardNumber},
+	{Kind: KindPassword, Match: looksLikePassword},
+}
+
+// Detect 使用默认检测器检测文本，返回命中的类别
+func Detect(text string) (Kind, bool) {
+	for _, d := range DefaultDetectors {
+		if d.Match(text) {
+			return d.Kind, true
+		}
+	}
+	return "", false
+}
+
+// containsCardNumber 检查文本中是否有通过 Luhn 校验的卡号
+func containsCardNumber(text string) bool {
+	for _, candidate := range cardRe.FindAllString(text, -1) {
+		digits := strings.NewReplacer(" ", "", "-", "").Replace(candidate)
+		if len(digits) >= 13 && len(digits) <= 19 && luhnValid(digits) {
+			return true
+		}
+	}
+	return false
+}
+
+// luhnValid Luhn 校验
+func luhnValid(digits string) bool {
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// looksLikePassword 判断是否为密码管理器生成的随机密码
+// 条件：单个无空白的词，长度 12~64，同时包含大写、小写、数字和符号，
+// 且字符足够分散（不含连续的小写单词、字符熵足够高）；邮箱、路径、标识符与函数调用不算
+func looksLikePassword(text string) bool {
+	text = strings.TrimSpace(text)
+	if len(text) < 12 || len(text) > 64 {
+		return false
+	}
+	if looksLikeIdentifier(text) {
+		return false
+	}
+
+	var upper, lower, digit, symbol bool
+	lowerRun, maxLowerRun := 0, 0
+	for _, r := range text {
+		if unicode.IsLower(r) {
+			lowerRun++
+			maxLowerRun = max(maxLowerRun, lowerRun)
+		} else {
+			lowerRun = 0
+		}
+		switch {
+		case unicode.IsSpace(r):
+			return false
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case r < unicode.MaxASCII && unicode.IsPrint(r):
+			symbol = true
+		default:
+			return false
+		}
+	}
+	if !(upper && lower && digit && symbol) {
+		return false
+	}
+	// 随机密码中很少出现 4 个以上连续小写字母，人写的词（如 Order、Println）则很常见
+	if maxLowerRun >= maxRandomLowerRun {
+		return false
+	}
+	return shannonEntropy(text) >= minPasswordEntropy
+}
+
+const (
+	maxRandomLowerRun  = 4   // 连续小写字母达到该长度时视为普通单词
+	minPasswordEntropy = 3.0 // 每个字符的最小香农熵（比特）
+)
+
+var (
+	emailRe       = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[A-Za-z]{2,}$`)
+	windowsPathRe = regexp.MustCompile(`^([A-Za-z]:[\\/]|\\\\)`)
+	callRe        = regexp.MustCompile(`^[A-Za-z_$][\w$.]*(\(.*\)|\[.*\])[;,]?$`)
+	qualifiedRe   = regexp.MustCompile(`^[A-Za-z_$][\w$]*(\.[A-Za-z_$][\w$]*)+$`)
+)
+
+// looksLikeIdentifier 是否为 URL、邮箱、路径、限定名或函数调用这类常见的长字符串
+func looksLikeIdentifier(text string) bool {
+	return strings.Contains(text, "://") ||
+		strings.HasPrefix(text, "/") ||
+		strings.HasPrefix(text, "~/") ||
+		emailRe.MatchString(text) ||
+		windowsPathRe.MatchString(text) ||
+		callRe.MatchString(text) ||
+		qualifiedRe.MatchString(text)
+}
+
+// shannonEntropy 计算每个字符的香农熵（比特）
+func shannonEntropy(text string) float64 {
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range text {
+		counts[r]++
+		total++
+	}
+	entropy := 0.0
+	for _, n := range counts {
+		p := float64(n) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// Mark 按配置检测文本项的敏感内容，命中时标记并设置过期时间
+func Mark(item *model.ClipboardItem, cfg config.SensitiveConfig) (Kind, bool) {
+	if !cfg.Enabled {
+		return "", false
+	}
+	kind, found := Detect(item.Content)
+	if !found {
+		return "", false
+	}
+	MarkKind(item, kind, cfg)
+	return kind, true
+}
+
+// MarkKind 将项标记为 kind 类敏感内容（如来源提示为密码），过期时间按配置计算；
+// 项已有更早的过期时间（如捕获规则设置）时保留原值
+func MarkKind(item *model.ClipboardItem, kind Kind, cfg config.SensitiveConfig) {
+	if !cfg.Enabled {
+		return
+	}
+	log.Printf("检测到敏感内容（%s），将在 %d 分钟后过期", kind, cfg.ExpireMinutes)
+	item.Sensitive = true
+	expiresAt := item.Timestamp.Add(time.Duration(cfg.ExpireMinutes) * time.Minute)
+	if item.ExpiresAt == nil || expiresAt.Before(*item.ExpiresAt) {
+		item.ExpiresAt = &expiresAt
+	}
+}
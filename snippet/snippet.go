@@ -0,0 +1,83 @@
+package snippet
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// placeholderRe 匹配 {{name}} 或 {{name:arg}} 形式的占位符
+var placeholderRe = regexp.MustCompile(`\{\{\s*([a-zA-Z]+)\s*(?::([^{}]*))?\}\}`)
+
+// Context 展开占位符所需的上下文
+type Context struct {
+	Clipboard string            // 当前剪贴板文本，用于 {{clipboard}}
+	Inputs    map[string]string // 用户输入，键为 {{input:标签}} 中的标签
+	Now       time.Time         // 当前时间，零值时取 time.Now()
+}
+
+// Inputs 返回片段中需要用户输入的标签（按出现顺序去重）
+func Inputs(content string) []string {
+	var labels []string
+	seen := map[string]bool{}
+	for _, m := range placeholderRe.FindAllStringSubmatch(content, -1) {
+		if strings.ToLower(m[1]) != "input" {
+			continue
+		}
+		label := strings.TrimSpace(m[2])
+		if !seen[label] {
+			seen[label] = true
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// HasPlaceholders 判断内容是否包含占位符
+func HasPlaceholders(content string) bool {
+	return placeholderRe.MatchString(content)
+}
+
+// Expand 展开片段中的占位符
+// 支持 {{date}}、{{date:Go时间格式}}、{{clipboard}}、{{uuid}}、{{input:标签}}
+func Expand(content string, ctx Context) (string, error) {
+	now := ctx.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	var expandErr error
+	result := placeholderRe.ReplaceAllStringFunc(content, func(match string) string {
+		m := placeholderRe.FindStringSubmatch(match)
+		name, arg := strings.ToLower(m[1]), strings.TrimSpace(m[2])
+
+		switch name {
+		case "date":
+			if arg == "" {
+				arg = "2006-01-02"
+			}
+			return now.Format(arg)
+		case "clipboard":
+			return ctx.Clipboard
+		case "uuid":
+			return uuid.New().String()
+		case "input":
+			value, ok := ctx.Inputs[arg]
+			if !ok && expandErr == nil {
+				expandErr = fmt.Errorf("缺少输入: %s", arg)
+			}
+			return value
+		default:
+			// 未知占位符原样保留
+			return match
+		}
+	})
+
+	if expandErr != nil {
+		return "", expandErr
+	}
+	return result, nil
+}
@@ -0,0 +1,95 @@
+package snippet
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestExpand(t *testing.T) {
+	now := time.Date(2024, 3, 5, 14, 30, 0, 0, time.UTC)
+	ctx := Context{
+		Clipboard: "剪贴板",
+		Inputs:    map[string]string{"姓名": "张三", "": "无标签"},
+		Now:       now,
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{"无占位符", "纯文本", "纯文本", false},
+		{"默认日期格式", "今天是 {{date}}", "今天是 2024-03-05", false},
+		{"自定义日期格式", "{{date:15:04}}", "14:30", false},
+		{"剪贴板", "引用：{{clipboard}}", "引用：剪贴板", false},
+		{"输入", "你好，{{input:姓名}}！", "你好，张三！", false},
+		{"同一输入多次出现", "{{input:姓名}}/{{input:姓名}}", "张三/张三", false},
+		{"占位符内的空白与大小写", "{{ Date : 2006 }}", "2024", false},
+		{"无标签输入", "{{input}}", "无标签", false},
+		{"未知占位符原样保留", "{{unknown}} {{x:y}}", "{{unknown}} {{x:y}}", false},
+		{"缺少输入", "{{input:邮箱}}", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Expand(tt.content, ctx)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expand(%q) 错误 = %v，期望出错 %v", tt.content, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Expand(%q) = %q，期望 %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandUUID(t *testing.T) {
+	uuidRe := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12} [0-9a-f-]{36}$`)
+	got, err := Expand("{{uuid}} {{uuid}}", Context{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !uuidRe.MatchString(got) {
+		t.Fatalf("展开结果 %q 不是两个 UUID", got)
+	}
+	if got[:36] == got[37:] {
+		t.Error("每个 {{uuid}} 应生成不同的值")
+	}
+}
+
+func TestInputs(t *testing.T) {
+	tests := []struct {
+		content string
+		want    []string
+	}{
+		{"没有输入", nil},
+		{"{{date}} {{clipboard}}", nil},
+		{"{{input:姓名}} {{ INPUT : 邮箱 }} {{input:姓名}}", []string{"姓名", "邮箱"}},
+	}
+	for _, tt := range tests {
+		if got := Inputs(tt.content); !slices.Equal(got, tt.want) {
+			t.Errorf("Inputs(%q) = %q，期望 %q", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestHasPlaceholders(t *testing.T) {
+	tests := []struct {
+		content string
+		want    bool
+	}{
+		{"普通文本", false},
+		{"{{date}}", true},
+		{"{{input:姓名}}", true},
+		{"{{}}", false},
+		{"{single}", false},
+	}
+	for _, tt := range tests {
+		if got := HasPlaceholders(tt.content); got != tt.want {
+			t.Errorf("HasPlaceholders(%q) = %v，期望 %v", tt.content, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package stats
+
+import (
+	"clipboard/filelist"
+	"clipboard/model"
+	"clipboard/storage"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	recentDays    = 14 // 按天统计的天数
+	topDomainsMax = 10 // 常用域名展示数量
+	mostPastedMax = 10 // 最常粘贴项展示数量
+	previewLength = 40 // 内容预览长度
+)
+
+// DayCount 某天新增的项数
+type DayCount struct {
+	Date  string `json:"date"` // 格式 2006-01-02
+	Count int    `json:"count"`
+}
+
+// DomainCount 域名出现次数
+type DomainCount struct {
+	Domain string `json:"domain"`
+	Count  int    `json:"count"`
+}
+
+// PastedItem 粘贴次数统计项
+type PastedItem struct {
+	ID         string     `json:"id"`
+	Type       string     `json:"type"`
+	Preview    string     `json:"preview"`
+	PasteCount int        `json:"pasteCount"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+}
+
+// StorageUsage 存储占用（字节）
+type StorageUsage struct {
+	DataFile      string `json:"dataFile,omitempty"`
+	DataBytes     int64  `json:"dataBytes"`
+	ImageDir      string `json:"imageDir"`
+	ImageBytes    int64  `json:"imageBytes"`
+	ImageFiles    int    `json:"imageFiles"`
+	SnapshotBytes int64  `json:"snapshotBytes"` // 文件快照占用
+	TotalBytes    int64  `json:"totalBytes"`
+}
+
+// Report 剪贴板活动统计结果
+type Report struct {
+	GeneratedAt time.Time      `json:"generatedAt"`
+	TotalItems  int            `json:"totalItems"`
+	Favorites   int            `json:"favorites"`
+	TotalPastes int            `json:"totalPastes"`
+	PerDay      []DayCount     `json:"perDay"`  // 最近 14 天，最早的在前
+	PerHour     [24]int        `json:"perHour"` // 按复制时刻的小时分布
+	Types       map[string]int `json:"types"`
+	TopDomains  []DomainCount  `json:"topDomains"`
+	MostPasted  []PastedItem   `json:"mostPasted"`
+	Storage     StorageUsage   `json:"storage"`
+}
+
+// dataFiler 使用本地数据文件的存储实现（如 JSON 存储）
+type dataFiler interface {
+	DataFile() string
+}
+
+// Service 基于存储计算统计数据
+type Service struct {
+	storage storage.Storage
+}
+
+// NewService 创建统计服务
+func NewService(s storage.Storage) *Service {
+	return &Service{storage: s}
+}
+
+// Compute 计算当前的统计数据
+func (s *Service) Compute() (*Report, error) {
+	items, err := s.storage.LoadItems()
+	if err != nil {
+		return nil, fmt.Errorf("加载历史记录失败: %w", err)
+	}
+
+	report := Build(items, time.Now())
+	report.Storage = s.storageUsage()
+	return report, nil
+}
+
+// JSON 计算统计数据并编码为 JSON，供命令行和 API 使用
+func (s *Service) JSON() ([]byte, error) {
+	report, err := s.Compute()
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(report, "", "  ")
+}
+
+// Build 根据历史项计算统计数据（不含存储占用）
+func Build(items []*model.ClipboardItem, now time.Time) *Report {
+	report := &Report{
+		GeneratedAt: now,
+		TotalItems:  len(items),
+		Types:       make(map[string]int),
+	}
+
+	// 最近 N 天的日期槽位
+	dayIndex := make(map[string]int, recentDays)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	for i := recentDays - 1; i >= 0; i-- {
+		date := today.AddDate(0, 0, -i).Format("2006-01-02")
+		dayIndex[date] = len(report.PerDay)
+		report.PerDay = append(report.PerDay, DayCount{Date: date})
+	}
+
+	domains := make(map[string]int)
+	var pasted []*model.ClipboardItem
+	for _, item := range items {
+		ts := item.Timestamp.In(now.Location())
+		if i, ok := dayIndex[ts.Format("2006-01-02")]; ok {
+			report.PerDay[i].Count++
+		}
+		report.PerHour[ts.Hour()]++
+		report.Types[typeKey(item)]++
+
+		if item.IsFavorite {
+			report.Favorites++
+		}
+		if item.PasteCount > 0 {
+			report.TotalPastes += item.PasteCount
+			pasted = append(pasted, item)
+		}
+		if domain := urlDomain(item); domain != "" {
+			domains[domain]++
+		}
+	}
+
+	for domain, count := range domains {
+		report.TopDomains = append(report.TopDomains, DomainCount{Domain: domain, Count: count})
+	}
+	sort.Slice(report.TopDomains, func(i, j int) bool {
+		a, b := report.TopDomains[i], report.TopDomains[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Domain < b.Domain
+	})
+	if len(report.TopDomains) > topDomainsMax {
+		report.TopDomains = report.TopDomains[:topDomainsMax]
+	}
+
+	model.SortItems(pasted, model.SortFrequent)
+	if len(pasted) > mostPastedMax {
+		pasted = pasted[:mostPastedMax]
+	}
+	for _, item := range pasted {
+		report.MostPasted = append(report.MostPasted, PastedItem{
+			ID:         item.ID,
+			Type:       item.Type.String(),
+			Preview:    preview(item),
+			PasteCount: item.PasteCount,
+			LastUsedAt: item.LastUsedAt,
+		})
+	}
+	return report
+}
+
+// typeKey 类型统计的键，文本按细分类型区分
+func typeKey(item *model.ClipboardItem) string {
+	if item.Type == model.TypeText && item.Subtype != "" && item.Subtype != model.SubtypePlain {
+		return item.Type.String() + "/" + string(item.Subtype)
+	}
+	return item.Type.String()
+}
+
+// urlDomain 返回网址类文本项的域名
+func urlDomain(item *model.ClipboardItem) string {
+	if item.Type != model.TypeText || item.Subtype != model.SubtypeURL || item.Sensitive {
+		return ""
+	}
+	u, err := url.Parse(strings.TrimSpace(item.Content))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// preview 生成内容预览，敏感内容不展示
+func preview(item *model.ClipboardItem) string {
+	switch {
+	case item.Sensitive:
+		return "[敏感内容]"
+	case item.Type == model.TypeImage:
+		return "[图片] " + filepath.Base(item.ImagePath)
+	}
+	text := []rune(strings.Join(strings.Fields(item.Content), " "))
+	if len(text) > previewLength {
+		return string(text[:previewLength]) + "..."
+	}
+	return string(text)
+}
+
+// storageUsage 统计数据文件、图片目录与文件快照目录的占用
+func (s *Service) storageUsage() StorageUsage {
+	usage := StorageUsage{ImageDir: s.storage.GetImagePath()}
+
+	if f, ok := s.storage.(dataFiler); ok {
+		usage.DataFile = f.DataFile()
+		if info, err := os.Stat(usage.DataFile); err == nil {
+			usage.DataBytes = info.Size()
+		}
+	}
+
+	if usage.ImageDir != "" {
+		usage.ImageBytes, usage.ImageFiles = dirSize(usage.ImageDir)
+		usage.SnapshotBytes, _ = dirSize(filelist.SnapshotDir(usage.ImageDir))
+	}
+
+	usage.TotalBytes = usage.DataBytes + usage.ImageBytes + usage.SnapshotBytes
+	return usage
+}
+
+// dirSize 统计目录下所有文件的总字节数和文件数
+func dirSize(dir string) (int64, int) {
+	var size int64
+	var files int
+	filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			size += info.Size()
+			files++
+		}
+		return nil
+	})
+	return size, files
+}
+
+// FormatBytes 将字节数格式化为易读的大小
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
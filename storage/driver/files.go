@@ -0,0 +1,21 @@
+package driver
+
+import (
+	"clipboard/filelist"
+	"clipboard/model"
+	"clipboard/thumbnail"
+	"os"
+)
+
+// removeItemFiles 删除项关联的本地文件（图片、缩略图、文件快照）
+func removeItemFiles(item *model.ClipboardItem) {
+	switch item.Type {
+	case model.TypeImage:
+		if item.ImagePath != "" {
+			os.Remove(item.ImagePath)
+			thumbnail.Remove(item.ImagePath)
+		}
+	case model.TypeFile:
+		filelist.RemoveSnapshots(item)
+	}
+}
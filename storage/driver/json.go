@@ -9,8 +9,8 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
-	"strings"
 	"sync"
+	"time"
 )
 
 // JSONStorage JSON文件存储实现
@@ -55,10 +55,15 @@ func NewJSONStorage(cfg *config.StorageConfig) (*JSONStorage, error) {
 
 // SaveItems 保存所有历史项
 func (s *JSONStorage) SaveItems(items []*model.ClipboardItem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.saveItems(items)
+}
+
+// saveItems 写入历史文件，调用方持有 s.mu
+func (s *JSONStorage) saveItems(items []*model.ClipboardItem) error {
 	// 确保不超过最大数量
-	if len(items) > s.config.MaxItems {
-		items = items[:s.config.MaxItems]
-	}
+	items = limitItems(items, s.config.MaxItems)
 
 	data, err := json.MarshalIndent(items, "", "  ")
 	if err != nil {
@@ -97,6 +102,9 @@ func (s *JSONStorage) LoadItems() ([]*model.ClipboardItem, error) {
 
 // AddItem 添加新项
 func (s *JSONStorage) AddItem(newItem *model.ClipboardItem) ([]*model.ClipboardItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	items, err := s.LoadItems()
 	if err != nil {
 		return nil, err
@@ -115,11 +123,9 @@ func (s *JSONStorage) AddItem(newItem *model.ClipboardItem) ([]*model.ClipboardI
 	items = append([]*model.ClipboardItem{newItem}, items...)
 
 	// 限制数量
-	if len(items) > s.config.MaxItems {
-		items = items[:s.config.MaxItems]
-	}
+	items = limitItems(items, s.config.MaxItems)
 
-	if err := s.SaveItems(items); err != nil {
+	if err := s.saveItems(items); err != nil {
 		return nil, err
 	}
 
@@ -142,10 +148,8 @@ func (s *JSONStorage) DeleteItem(id string) ([]*model.ClipboardItem, error) {
 	for _, item := range items {
 		if item.ID == id {
 			found = true
-			// 处理图片文件删除
-			if item.Type == model.TypeImage && item.ImagePath != "" {
-				os.Remove(item.ImagePath)
-			}
+			// 删除关联的图片、快照文件
+			removeItemFiles(item)
 			continue
 		}
 		newItems = append(newItems, item)
@@ -157,7 +161,7 @@ func (s *JSONStorage) DeleteItem(id string) ([]*model.ClipboardItem, error) {
 	}
 
 	// 立即保存并返回最新数据
-	if err := s.SaveItems(newItems); err != nil {
+	if err := s.saveItems(newItems); err != nil {
 		return nil, err
 	}
 
@@ -165,9 +169,171 @@ func (s *JSONStorage) DeleteItem(id string) ([]*model.ClipboardItem, error) {
 	return newItems, nil
 }
 
+// ReplaceItems 删除一组项并添加新项，一次写入文件
+func (s *JSONStorage) ReplaceItems(removeIDs []string, newItems []*model.ClipboardItem) ([]*model.ClipboardItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items, err := s.LoadItems()
+	if err != nil {
+		return nil, err
+	}
+
+	remove := make(map[string]bool, len(removeIDs))
+	for _, id := range removeIDs {
+		remove[id] = true
+	}
+
+	kept := make([]*model.ClipboardItem, 0, len(items)+len(newItems))
+	kept = append(kept, newItems...)
+	for _, item := range items {
+		if remove[item.ID] {
+			delete(remove, item.ID)
+			removeItemFiles(item)
+			continue
+		}
+		kept = append(kept, item)
+	}
+
+	if len(remove) > 0 {
+		return nil, fmt.Errorf("未找到 %d 个待替换的项", len(remove))
+	}
+
+	kept = limitItems(kept, s.config.MaxItems)
+	if err := s.saveItems(kept); err != nil {
+		return nil, err
+	}
+	return kept, nil
+}
+
+// UpdateItem 保存编辑后的项
+func (s *JSONStorage) UpdateItem(updated *model.ClipboardItem) ([]*model.ClipboardItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items, err := s.LoadItems()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		if item.ID != updated.ID {
+			continue
+		}
+
+		now := time.Now()
+		if item.Content != updated.Content {
+			item.Revisions = append(item.Revisions, model.Revision{
+				ID:        uint(len(item.Revisions) + 1),
+				Content:   item.Content,
+				CreatedAt: now,
+			})
+		}
+		item.Content = updated.Content
+		item.Subtype = updated.Subtype
+		item.Language = updated.Language
+		item.Tags = updated.Tags
+		item.UpdatedAt = now
+
+		if err := s.saveItems(items); err != nil {
+			return nil, err
+		}
+		return items, nil
+	}
+
+	return nil, fmt.Errorf("未找到ID为 %s 的项", updated.ID)
+}
+
+// Revisions 获取项的历史版本
+func (s *JSONStorage) Revisions(id string) ([]model.Revision, error) {
+	items, err := s.LoadItems()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		if item.ID == id {
+			revisions := make([]model.Revision, 0, len(item.Revisions))
+			for i := len(item.Revisions) - 1; i >= 0; i-- {
+				revisions = append(revisions, item.Revisions[i])
+			}
+			return revisions, nil
+		}
+	}
+	return nil, fmt.Errorf("未找到ID为 %s 的项", id)
+}
+
+// RecordUse 记录项被写回剪贴板一次
+func (s *JSONStorage) RecordUse(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items, err := s.LoadItems()
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if item.ID == id {
+			now := time.Now()
+			item.PasteCount++
+			item.LastUsedAt = &now
+			return s.saveItems(items)
+		}
+	}
+	return fmt.Errorf("未找到ID为 %s 的项", id)
+}
+
+// BumpSimilarImage 将与给定哈希相似的图片项时间戳更新为 at
+func (s *JSONStorage) BumpSimilarImage(hash string, maxDistance int, at time.Time) ([]*model.ClipboardItem, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items, err := s.LoadItems()
+	if err != nil {
+		return nil, false, err
+	}
+
+	similar := closestImage(items, hash, maxDistance)
+	if similar == nil {
+		return items, false, nil
+	}
+
+	similar.Timestamp = at
+	if err := s.saveItems(items); err != nil {
+		return nil, false, err
+	}
+	items, err = s.LoadItems()
+	if err != nil {
+		return nil, false, err
+	}
+	return items, true, nil
+}
+
+// SetImageMeta 批量更新图片项的元数据
+func (s *JSONStorage) SetImageMeta(metas map[string]model.ImageMeta) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items, err := s.LoadItems()
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if meta, ok := metas[item.ID]; ok {
+			item.ImageMeta = meta
+		}
+	}
+	return s.saveItems(items)
+}
+
 // ToggleFavorite 切换收藏状态
 func (s *JSONStorage) ToggleFavorite(id string) ([]*model.ClipboardItem, error) {
 	log.Printf("切换收藏状态，ID: %s", id)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	items, err := s.LoadItems()
 	if err != nil {
 		log.Printf("加载项失败: %v", err)
@@ -188,7 +354,7 @@ func (s *JSONStorage) ToggleFavorite(id string) ([]*model.ClipboardItem, error)
 		return nil, fmt.Errorf("未找到ID为 %s 的项", id)
 	}
 
-	if err := s.SaveItems(items); err != nil {
+	if err := s.saveItems(items); err != nil {
 		log.Printf("保存收藏状态失败: %v", err)
 		return nil, err
 	}
@@ -215,21 +381,76 @@ func (s *JSONStorage) Search(keyword string) ([]*model.ClipboardItem, error) {
 		return items, nil
 	}
 
+	query := parseQuery(keyword)
 	var results []*model.ClipboardItem
 	for _, item := range items {
-		if strings.Contains(strings.ToLower(item.Content), strings.ToLower(keyword)) {
+		if query.match(item) {
 			results = append(results, item)
 		}
 	}
 
+	model.SortItems(results, model.SortFrecency)
+
 	return results, nil
 }
 
+// PurgeExpired 删除已过期的项
+func (s *JSONStorage) PurgeExpired() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items, err := s.LoadItems()
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	kept := make([]*model.ClipboardItem, 0, len(items))
+	for _, item := range items {
+		if item.IsExpired(now) {
+			removeItemFiles(item)
+			continue
+		}
+		kept = append(kept, item)
+	}
+
+	removed := len(items) - len(kept)
+	if removed == 0 {
+		return 0, nil
+	}
+
+	if err := s.saveItems(kept); err != nil {
+		return 0, err
+	}
+	return removed, nil
+}
+
+// limitItems 按最大数量截断历史项，片段不计入数量也不会被淘汰
+func limitItems(items []*model.ClipboardItem, maxItems int) []*model.ClipboardItem {
+	kept := make([]*model.ClipboardItem, 0, len(items))
+	count := 0
+	for _, item := range items {
+		if item.Type != model.TypeSnippet {
+			if count >= maxItems {
+				continue
+			}
+			count++
+		}
+		kept = append(kept, item)
+	}
+	return kept
+}
+
 // GetImagePath 获取图片存储路径
 func (s *JSONStorage) GetImagePath() string {
 	return s.imagePath
 }
 
+// DataFile 获取历史记录数据文件路径
+func (s *JSONStorage) DataFile() string {
+	return s.filePath
+}
+
 // Close 关闭存储
 func (s *JSONStorage) Close() error {
 	return nil
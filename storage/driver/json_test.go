@@ -0,0 +1,60 @@
+package driver
+
+import (
+	"clipboard/model"
+	"slices"
+	"testing"
+)
+
+func TestLimitItems(t *testing.T) {
+	item := func(id string, itemType model.ItemType) *model.ClipboardItem {
+		return &model.ClipboardItem{ID: id, Type: itemType}
+	}
+
+	tests := []struct {
+		name     string
+		items    []*model.ClipboardItem
+		maxItems int
+		want     []string
+	}{
+		{
+			name:     "未超出上限",
+			items:    []*model.ClipboardItem{item("a", model.TypeText), item("b", model.TypeImage)},
+			maxItems: 5,
+			want:     []string{"a", "b"},
+		},
+		{
+			name:     "超出上限时保留前面的项",
+			items:    []*model.ClipboardItem{item("a", model.TypeText), item("b", model.TypeText), item("c", model.TypeFile)},
+			maxItems: 2,
+			want:     []string{"a", "b"},
+		},
+		{
+			name: "片段不计入上限且不被移除",
+			items: []*model.ClipboardItem{
+				item("s1", model.TypeSnippet), item("a", model.TypeText),
+				item("b", model.TypeText), item("s2", model.TypeSnippet), item("c", model.TypeText),
+			},
+			maxItems: 2,
+			want:     []string{"s1", "a", "b", "s2"},
+		},
+		{
+			name:     "上限为 0 时只保留片段",
+			items:    []*model.ClipboardItem{item("a", model.TypeText), item("s", model.TypeSnippet)},
+			maxItems: 0,
+			want:     []string{"s"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, it := range limitItems(tt.items, tt.maxItems) {
+				got = append(got, it.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("limitItems = %v，期望 %v", got, tt.want)
+			}
+		})
+	}
+}
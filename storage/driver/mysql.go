@@ -8,6 +8,7 @@ import (
 	"gorm.io/gorm"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 // MySQLStorage MySQL存储实现（使用GORM）
@@ -35,7 +36,7 @@ func NewMySQLStorage(cfg *config.StorageConfig) (*MySQLStorage, error) {
 	}
 
 	// 自动迁移表结构
-	if err := db.AutoMigrate(&model.ClipboardItem{}); err != nil {
+	if err := db.AutoMigrate(&model.ClipboardItem{}, &model.Revision{}); err != nil {
 		return nil, fmt.Errorf("迁移表结构失败: %v", err)
 	}
 
@@ -71,8 +72,9 @@ func (s *MySQLStorage) SaveItems(items []*model.ClipboardItem) error {
 func (s *MySQLStorage) LoadItems() ([]*model.ClipboardItem, error) {
 	var items []*model.ClipboardItem
 
-	// 只按时间降序排序
-	result := s.db.Order("timestamp DESC").
+	// 只按时间降序排序（片段不受数量限制，单独加载）
+	result := s.db.Where("type <> ?", model.TypeSnippet).
+		Order("timestamp DESC").
 		Limit(s.config.MaxItems).
 		Find(&items)
 
@@ -80,7 +82,14 @@ func (s *MySQLStorage) LoadItems() ([]*model.ClipboardItem, error) {
 		return nil, result.Error
 	}
 
-	return items, nil
+	var snippets []*model.ClipboardItem
+	if err := s.db.Where("type = ?", model.TypeSnippet).
+		Order("timestamp DESC").
+		Find(&snippets).Error; err != nil {
+		return nil, err
+	}
+
+	return append(items, snippets...), nil
 }
 
 // AddItem 添加新项
@@ -108,7 +117,8 @@ func (s *MySQLStorage) AddItem(newItem *model.ClipboardItem) ([]*model.Clipboard
 
 	// 获取超过最大数量的记录ID
 	var oldItems []model.ClipboardItem
-	if err := s.db.Order("is_favorite DESC, timestamp ASC").
+	if err := s.db.Where("type <> ?", model.TypeSnippet).
+		Order("is_favorite DESC, timestamp ASC").
 		Offset(s.config.MaxItems).
 		Find(&oldItems).Error; err != nil {
 		return nil, err
@@ -121,18 +131,9 @@ func (s *MySQLStorage) AddItem(newItem *model.ClipboardItem) ([]*model.Clipboard
 			ids = append(ids, item.ID)
 		}
 
-		// 删除前先获取图片路径
-		var imageItems []model.ClipboardItem
-		if err := s.db.Where("id IN ? AND type = ?", ids, model.TypeImage).
-			Find(&imageItems).Error; err != nil {
-			return nil, err
-		}
-
-		// 删除图片文件
-		for _, item := range imageItems {
-			if item.ImagePath != "" {
-				os.Remove(item.ImagePath)
-			}
+		// 删除关联的图片、快照文件
+		for i := range oldItems {
+			removeItemFiles(&oldItems[i])
 		}
 
 		// 从数据库删除
@@ -153,10 +154,8 @@ func (s *MySQLStorage) DeleteItem(id string) ([]*model.ClipboardItem, error) {
 		return nil, err
 	}
 
-	// 如果是图片，删除文件
-	if item.Type == model.TypeImage && item.ImagePath != "" {
-		os.Remove(item.ImagePath)
-	}
+	// 删除关联的图片、快照文件
+	removeItemFiles(&item)
 
 	// 从数据库删除
 	if err := s.db.Delete(&model.ClipboardItem{}, "id = ?", id).Error; err != nil {
@@ -167,6 +166,152 @@ func (s *MySQLStorage) DeleteItem(id string) ([]*model.ClipboardItem, error) {
 	return s.LoadItems()
 }
 
+// ReplaceItems 在同一事务中删除一组项并添加新项
+func (s *MySQLStorage) ReplaceItems(removeIDs []string, newItems []*model.ClipboardItem) ([]*model.ClipboardItem, error) {
+	var removed []model.ClipboardItem
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if len(removeIDs) > 0 {
+			if err := tx.Where("id IN ?", removeIDs).Find(&removed).Error; err != nil {
+				return err
+			}
+			if len(removed) != len(removeIDs) {
+				return fmt.Errorf("未找到 %d 个待替换的项", len(removeIDs)-len(removed))
+			}
+			if err := tx.Where("id IN ?", removeIDs).Delete(&model.ClipboardItem{}).Error; err != nil {
+				return err
+			}
+		}
+		if len(newItems) > 0 {
+			// 撤销删除时会以原 ID 重新添加，先清除软删除留下的同 ID 记录，避免主键冲突
+			ids := make([]string, len(newItems))
+			for i, item := range newItems {
+				ids[i] = item.ID
+			}
+			if err := tx.Unscoped().Where("id IN ? AND deleted_at IS NOT NULL", ids).
+				Delete(&model.ClipboardItem{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(newItems).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 事务提交后再删除关联文件，避免回滚时文件已丢失
+	for i := range removed {
+		removeItemFiles(&removed[i])
+	}
+
+	return s.LoadItems()
+}
+
+// UpdateItem 在同一事务中记录历史版本并保存编辑后的项
+func (s *MySQLStorage) UpdateItem(updated *model.ClipboardItem) ([]*model.ClipboardItem, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var existing model.ClipboardItem
+		if err := tx.First(&existing, "id = ?", updated.ID).Error; err != nil {
+			return err
+		}
+
+		if existing.Content != updated.Content {
+			revision := model.Revision{ItemID: existing.ID, Content: existing.Content}
+			if err := tx.Create(&revision).Error; err != nil {
+				return err
+			}
+		}
+
+		// 使用 Select 确保空值也被更新，updated_at 由 GORM 维护
+		return tx.Model(&existing).
+			Select("content", "subtype", "language", "tags").
+			Updates(&model.ClipboardItem{
+				Content:  updated.Content,
+				Subtype:  updated.Subtype,
+				Language: updated.Language,
+				Tags:     updated.Tags,
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.LoadItems()
+}
+
+// Revisions 获取项的历史版本
+func (s *MySQLStorage) Revisions(id string) ([]model.Revision, error) {
+	var revisions []model.Revision
+	if err := s.db.Where("item_id = ?", id).
+		Order("created_at DESC, id DESC").
+		Find(&revisions).Error; err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// RecordUse 记录项被写回剪贴板一次
+// 使用 UpdateColumns 避免刷新 updated_at（使用不算编辑）
+func (s *MySQLStorage) RecordUse(id string) error {
+	result := s.db.Model(&model.ClipboardItem{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"paste_count":  gorm.Expr("paste_count + 1"),
+			"last_used_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("未找到ID为 %s 的项", id)
+	}
+	return nil
+}
+
+// BumpSimilarImage 将与给定哈希相似的图片项时间戳更新为 at
+func (s *MySQLStorage) BumpSimilarImage(hash string, maxDistance int, at time.Time) ([]*model.ClipboardItem, bool, error) {
+	var candidates []*model.ClipboardItem
+	if err := s.db.Select("id", "type", "image_hash").
+		Where("type = ? AND image_hash <> ''", model.TypeImage).
+		Find(&candidates).Error; err != nil {
+		return nil, false, err
+	}
+
+	similar := closestImage(candidates, hash, maxDistance)
+	if similar == nil {
+		items, err := s.LoadItems()
+		return items, false, err
+	}
+
+	if err := s.db.Model(&model.ClipboardItem{}).
+		Where("id = ?", similar.ID).
+		UpdateColumn("timestamp", at).Error; err != nil {
+		return nil, false, err
+	}
+	items, err := s.LoadItems()
+	if err != nil {
+		return nil, false, err
+	}
+	return items, true, nil
+}
+
+// SetImageMeta 批量更新图片项的元数据
+func (s *MySQLStorage) SetImageMeta(metas map[string]model.ImageMeta) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		for id, meta := range metas {
+			if err := tx.Model(&model.ClipboardItem{}).
+				Where("id = ?", id).
+				Select("width", "height", "byte_size", "format", "image_hash", "colors").
+				UpdateColumns(&model.ClipboardItem{ImageMeta: meta}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 // ToggleFavorite 切换收藏状态
 func (s *MySQLStorage) ToggleFavorite(id string) ([]*model.ClipboardItem, error) {
 	// 使用GORM的更新功能切换收藏状态
@@ -193,18 +338,68 @@ func (s *MySQLStorage) Search(keyword string) ([]*model.ClipboardItem, error) {
 		return s.LoadItems()
 	}
 
+	query := parseQuery(keyword)
+	db := s.db.Model(&model.ClipboardItem{})
+	if query.keyword != "" {
+		db = db.Where("content LIKE ?", "%"+query.keyword+"%")
+	}
+	if query.itemType != nil {
+		db = db.Where("type = ?", *query.itemType)
+	}
+	if query.subtype != "" {
+		db = db.Where("subtype = ?", query.subtype)
+	}
+	if query.language != "" {
+		db = db.Where("language = ?", query.language)
+	}
+	if query.imageOnly() {
+		db = db.Where("type = ?", model.TypeImage)
+	}
+	if query.format != "" {
+		db = db.Where("format = ?", query.format)
+	}
+	for _, f := range query.sizes {
+		// 列名与运算符均来自 sizeFilterRe 的白名单
+		db = db.Where(f.column+" "+f.op+" ?", f.value)
+	}
+
 	var items []*model.ClipboardItem
-	result := s.db.Where("content LIKE ?", "%"+keyword+"%").
-		Order("is_favorite DESC, timestamp DESC").
+	result := db.Order("is_favorite DESC, timestamp DESC").
 		Find(&items)
 
 	if result.Error != nil {
 		return nil, result.Error
 	}
 
+	model.SortItems(items, model.SortFrecency)
+
 	return items, nil
 }
 
+// PurgeExpired 删除已过期的项
+func (s *MySQLStorage) PurgeExpired() (int, error) {
+	var expired []model.ClipboardItem
+	if err := s.db.Where("expires_at IS NOT NULL AND expires_at <= ?", time.Now()).
+		Find(&expired).Error; err != nil {
+		return 0, err
+	}
+	if len(expired) == 0 {
+		return 0, nil
+	}
+
+	var ids []string
+	for _, item := range expired {
+		ids = append(ids, item.ID)
+		removeItemFiles(&item)
+	}
+
+	result := s.db.Where("id IN ?", ids).Delete(&model.ClipboardItem{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return int(result.RowsAffected), nil
+}
+
 // GetImagePath 获取图片存储路径
 func (s *MySQLStorage) GetImagePath() string {
 	return s.imagePath
@@ -0,0 +1,167 @@
+package driver
+
+import (
+	"clipboard/model"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// searchQuery 解析后的搜索条件
+// 关键词中形如 type:url、lang:go、format:png、width>1920 的片段作为过滤条件，其余部分作为全文关键词
+// 图片格式与尺寸条件只匹配图片项
+type searchQuery struct {
+	keyword  string
+	itemType *model.ItemType
+	subtype  model.TextSubtype
+	language string
+	format   string
+	sizes    []sizeFilter
+}
+
+// sizeFilter 图片宽、高、字节数的比较条件
+type sizeFilter struct {
+	column string // 数据库列名：width、height、byte_size
+	op     string // >、>=、<、<=、=
+	value  int64
+}
+
+// sizeFilterRe 匹配 width>1920、height<=1080、size>500kb 等条件
+var sizeFilterRe = regexp.MustCompile(`^(?i)(width|height|size)(>=|<=|>|<|=)(\d+)(kb|mb|k|m)?$`)
+
+// parseSizeFilter 解析尺寸比较条件
+func parseSizeFilter(field string) (sizeFilter, bool) {
+	m := sizeFilterRe.FindStringSubmatch(field)
+	if m == nil {
+		return sizeFilter{}, false
+	}
+	value, err := strconv.ParseInt(m[3], 10, 64)
+	if err != nil {
+		return sizeFilter{}, false
+	}
+
+	column := strings.ToLower(m[1])
+	if column == "size" {
+		column = "byte_size"
+		switch strings.ToLower(m[4]) {
+		case "kb", "k":
+			value *= 1 << 10
+		case "mb", "m":
+			value *= 1 << 20
+		}
+	}
+	return sizeFilter{column: column, op: m[2], value: value}, true
+}
+
+// valueOf 取项对应的数值
+func (f sizeFilter) valueOf(item *model.ClipboardItem) int64 {
+	switch f.column {
+	case "width":
+		return int64(item.Width)
+	case "height":
+		return int64(item.Height)
+	default:
+		return item.ByteSize
+	}
+}
+
+// match 判断数值是否满足条件
+func (f sizeFilter) match(item *model.ClipboardItem) bool {
+	v := f.valueOf(item)
+	switch f.op {
+	case ">":
+		return v > f.value
+	case ">=":
+		return v >= f.value
+	case "<":
+		return v < f.value
+	case "<=":
+		return v <= f.value
+	default:
+		return v == f.value
+	}
+}
+
+// imageOnly 是否包含只适用于图片的条件
+func (q searchQuery) imageOnly() bool {
+	return q.format != "" || len(q.sizes) > 0
+}
+
+// parseQuery 解析搜索关键词
+func parseQuery(raw string) searchQuery {
+	var q searchQuery
+	var words []string
+
+	for _, field := range strings.Fields(raw) {
+		if f, ok := parseSizeFilter(field); ok {
+			q.sizes = append(q.sizes, f)
+			continue
+		}
+
+		key, value, ok := strings.Cut(field, ":")
+		if !ok || value == "" {
+			words = append(words, field)
+			continue
+		}
+
+		switch strings.ToLower(key) {
+		case "type":
+			value = strings.ToLower(value)
+			if t, ok := parseItemType(value); ok {
+				q.itemType = &t
+			} else {
+				q.subtype = model.TextSubtype(value)
+			}
+		case "lang":
+			q.language = strings.ToLower(value)
+		case "format":
+			q.format = strings.ToLower(value)
+			if q.format == "jpg" {
+				q.format = "jpeg"
+			}
+		default:
+			words = append(words, field)
+		}
+	}
+
+	q.keyword = strings.Join(words, " ")
+	return q
+}
+
+// match 判断项是否满足搜索条件（JSON 存储在内存中过滤使用）
+func (q searchQuery) match(item *model.ClipboardItem) bool {
+	if q.itemType != nil && item.Type != *q.itemType {
+		return false
+	}
+	if q.subtype != "" && item.Subtype != q.subtype {
+		return false
+	}
+	if q.language != "" && item.Language != q.language {
+		return false
+	}
+	if q.imageOnly() && item.Type != model.TypeImage {
+		return false
+	}
+	if q.format != "" && item.Format != q.format {
+		return false
+	}
+	for _, f := range q.sizes {
+		if !f.match(item) {
+			return false
+		}
+	}
+	if q.keyword != "" && !strings.Contains(strings.ToLower(item.Content), strings.ToLower(q.keyword)) {
+		return false
+	}
+	return true
+}
+
+// parseItemType 解析内容类型名称
+func parseItemType(name string) (model.ItemType, bool) {
+	for _, t := range []model.ItemType{model.TypeText, model.TypeImage, model.TypeFile, model.TypeSnippet} {
+		if t.String() == name {
+			return t, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,30 @@
+package driver
+
+import (
+	"clipboard/imagehash"
+	"clipboard/model"
+)
+
+// closestImage 返回感知哈希与 hash 最相近且距离不超过 maxDistance 的图片项
+func closestImage(items []*model.ClipboardItem, hash string, maxDistance int) *model.ClipboardItem {
+	target, err := imagehash.Parse(hash)
+	if err != nil {
+		return nil
+	}
+
+	var closest *model.ClipboardItem
+	best := maxDistance + 1
+	for _, item := range items {
+		if item.Type != model.TypeImage || item.ImageHash == "" {
+			continue
+		}
+		h, err := imagehash.Parse(item.ImageHash)
+		if err != nil {
+			continue
+		}
+		if d := imagehash.Distance(target, h); d < best {
+			closest, best = item, d
+		}
+	}
+	return closest
+}
@@ -1,6 +1,9 @@
 package storage
 
-import "clipboard/model"
+import (
+	"clipboard/model"
+	"time"
+)
 
 // Storage 存储接口定义
 type Storage interface {
@@ -16,12 +19,34 @@ type Storage interface {
 	// DeleteItem 删除项
 	DeleteItem(id string) ([]*model.ClipboardItem, error)
 
+	// ReplaceItems 原子地删除一组项并添加新项（合并、拆分与撤销删除使用）
+	ReplaceItems(removeIDs []string, newItems []*model.ClipboardItem) ([]*model.ClipboardItem, error)
+
+	// UpdateItem 保存编辑后的项，内容变化时将旧内容记为历史版本
+	UpdateItem(item *model.ClipboardItem) ([]*model.ClipboardItem, error)
+
+	// Revisions 获取项的历史版本（最新的在前）
+	Revisions(id string) ([]model.Revision, error)
+
+	// RecordUse 记录项被写回剪贴板一次（粘贴次数加一并更新最后使用时间）
+	RecordUse(id string) error
+
+	// BumpSimilarImage 查找感知哈希与 hash 的汉明距离不超过 maxDistance 的图片项，
+	// 找到时将最相近一项的时间戳更新为 at，并返回 true
+	BumpSimilarImage(hash string, maxDistance int, at time.Time) ([]*model.ClipboardItem, bool, error)
+
+	// SetImageMeta 批量更新图片项的元数据（键为项ID），用于补全旧数据
+	SetImageMeta(metas map[string]model.ImageMeta) error
+
 	// ToggleFavorite 切换收藏状态
 	ToggleFavorite(id string) ([]*model.ClipboardItem, error)
 
-	// Search 搜索项
+	// Search 搜索项，有关键词时按综合得分（frecency）排序
 	Search(keyword string) ([]*model.ClipboardItem, error)
 
+	// PurgeExpired 删除已过期的项，返回删除数量
+	PurgeExpired() (int, error)
+
 	// GetImagePath 获取图片存储路径
 	GetImagePath() string
 
@@ -0,0 +1,130 @@
+package thumbnail
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MaxSize 缩略图最长边的像素数
+const MaxSize = 96
+
+// dirName 缩略图目录名，位于图片目录下
+const dirName = "thumbs"
+
+// Path 返回图片对应的缩略图路径
+func Path(imagePath string) string {
+	base := strings.TrimSuffix(filepath.Base(imagePath), filepath.Ext(imagePath))
+	return filepath.Join(filepath.Dir(imagePath), dirName, base+".png")
+}
+
+// Generate 为图片生成缩略图（已存在时覆盖），返回缩略图路径
+func Generate(imagePath string) (string, error) {
+	file, err := os.Open(imagePath)
+	if err != nil {
+		return "", fmt.Errorf("打开图片失败: %w", err)
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return "", fmt.Errorf("图片解码失败: %w", err)
+	}
+
+	thumbPath := Path(imagePath)
+	if err := os.MkdirAll(filepath.Dir(thumbPath), 0755); err != nil {
+		return "", fmt.Errorf("创建缩略图目录失败: %w", err)
+	}
+
+	out, err := os.Create(thumbPath)
+	if err != nil {
+		return "", fmt.Errorf("创建缩略图文件失败: %w", err)
+	}
+	defer out.Close()
+
+	if err := png.Encode(out, Scale(img, MaxSize)); err != nil {
+		return "", fmt.Errorf("缩略图编码失败: %w", err)
+	}
+	return thumbPath, nil
+}
+
+// Ensure 返回图片的缩略图路径，缩略图缺失或比原图旧时重新生成
+func Ensure(imagePath string) (string, error) {
+	if _, err := os.Stat(imagePath); err != nil {
+		return "", fmt.Errorf("图片文件不存在: %w", err)
+	}
+	if thumbPath, ok := Cached(imagePath); ok {
+		return thumbPath, nil
+	}
+	return Generate(imagePath)
+}
+
+// Cached 返回已生成的缩略图路径，只检查文件，缩略图缺失或比原图旧时返回 false
+func Cached(imagePath string) (string, bool) {
+	src, err := os.Stat(imagePath)
+	if err != nil {
+		return "", false
+	}
+	thumbPath := Path(imagePath)
+	if thumb, err := os.Stat(thumbPath); err == nil && !thumb.ModTime().Before(src.ModTime()) {
+		return thumbPath, true
+	}
+	return "", false
+}
+
+// Remove 删除图片对应的缩略图
+func Remove(imagePath string) {
+	os.Remove(Path(imagePath))
+}
+
+// Scale 按比例缩小图片，使最长边不超过 maxSize（区域平均采样）
+func Scale(img image.Image, maxSize int) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w <= maxSize && h <= maxSize {
+		return img
+	}
+
+	dw, dh := maxSize, h*maxSize/w
+	if h > w {
+		dw, dh = w*maxSize/h, maxSize
+	}
+	dw, dh = max(dw, 1), max(dh, 1)
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		y0 := bounds.Min.Y + y*h/dh
+		y1 := max(bounds.Min.Y+(y+1)*h/dh, y0+1)
+		for x := 0; x < dw; x++ {
+			x0 := bounds.Min.X + x*w/dw
+			x1 := max(bounds.Min.X+(x+1)*w/dw, x0+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			// 预乘 alpha 的平均值转换回非预乘颜色
+			c := color.NRGBA64{}
+			if a > 0 {
+				c = color.NRGBA64{
+					R: uint16(r * 0xffff / a),
+					G: uint16(g * 0xffff / a),
+					B: uint16(b * 0xffff / a),
+					A: uint16(a / n),
+				}
+			}
+			dst.Set(x, y, c)
+		}
+	}
+	return dst
+}
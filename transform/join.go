@@ -0,0 +1,39 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+)
+
+// JoinOptions 合并多个文本项的选项
+type JoinOptions struct {
+	Separator string // 项之间的分隔符
+	Numbered  bool   // 是否在每项前加序号 "1. "
+	Quote     string // 包裹每项的引号，为空表示不加
+}
+
+// Join 按选项合并多段文本
+func Join(texts []string, opts JoinOptions) string {
+	parts := make([]string, len(texts))
+	for i, text := range texts {
+		if opts.Quote != "" {
+			text = opts.Quote + strings.ReplaceAll(text, opts.Quote, `\`+opts.Quote) + opts.Quote
+		}
+		if opts.Numbered {
+			text = fmt.Sprintf("%d. %s", i+1, text)
+		}
+		parts[i] = text
+	}
+	return strings.Join(parts, opts.Separator)
+}
+
+// Split 将多行文本拆分为非空的行
+func Split(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
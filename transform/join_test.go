@@ -0,0 +1,48 @@
+package transform
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestJoin(t *testing.T) {
+	texts := []string{"a", `b"c`, "d"}
+	tests := []struct {
+		name string
+		opts JoinOptions
+		want string
+	}{
+		{"按换行合并", JoinOptions{Separator: "\n"}, "a\nb\"c\nd"},
+		{"加序号", JoinOptions{Separator: "\n", Numbered: true}, "1. a\n2. b\"c\n3. d"},
+		{"加引号并转义", JoinOptions{Separator: ", ", Quote: `"`}, `"a", "b\"c", "d"`},
+		{"序号在引号外", JoinOptions{Separator: " ", Numbered: true, Quote: "'"}, `1. 'a' 2. 'b"c' 3. 'd'`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Join(texts, tt.opts); got != tt.want {
+				t.Errorf("Join = %q，期望 %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"单行", "a", []string{"a"}},
+		{"跳过空行", "a\n\n  \nb\n", []string{"a", "b"}},
+		{"CRLF 换行", "a\r\nb", []string{"a", "b"}},
+		{"保留行内空白", "  a  \nb", []string{"  a  ", "b"}},
+		{"只有空白", " \n\t", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Split(tt.text); !slices.Equal(got, tt.want) {
+				t.Errorf("Split(%q) = %q，期望 %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package transform
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// Func 文本转换函数
+type Func func(string) (string, error)
+
+// Transform 可用的转换
+type Transform struct {
+	Name  string // 唯一名称，用于配置中的预设
+	Label string // 显示名称
+	Apply Func
+}
+
+// trackingParams 需要移除的网址跟踪参数（精确匹配）
+var trackingParams = map[string]bool{
+	"fbclid": true, "gclid": true, "dclid": true, "msclkid": true, "yclid": true,
+	"mc_cid": true, "mc_eid": true, "igshid": true, "spm": true, "ref_src": true,
+	"_hsenc": true, "_hsmi": true, "vero_id": true, "si": true,
+}
+
+// All 所有可用转换，按菜单显示顺序排列
+var All = []Transform{
+	{"trim", "去除首尾空白", func(s string) (string, error) { return strings.TrimSpace(s), nil }},
+	{"upper", "转大写", func(s string) (string, error) { return strings.ToUpper(s), nil }},
+	{"lower", "转小写", func(s string) (string, error) { return strings.ToLower(s), nil }},
+	{"title", "单词首字母大写", titleCase},
+	{"json_pretty", "JSON 格式化", jsonPretty},
+	{"json_minify", "JSON 压缩", jsonMinify},
+	{"base64_encode", "Base64 编码", func(s string) (string, error) {
+		return base64.StdEncoding.EncodeToString([]byte(s)), nil
+	}},
+	{"base64_decode", "Base64 解码", base64Decode},
+	{"url_encode", "URL 编码", func(s string) (string, error) { return url.QueryEscape(s), nil }},
+	{"url_decode", "URL 解码", url.QueryUnescape},
+	{"strip_tracking", "移除网址跟踪参数", stripTracking},
+	{"shell_escape", "Shell 转义", shellEscape},
+	{"to_lf", "换行符转为 LF", func(s string) (string, error) {
+		return strings.ReplaceAll(s, "\r\n", "\n"), nil
+	}},
+	{"to_crlf", "换行符转为 CRLF", func(s string) (string, error) {
+		return strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", "\n"), "\n", "\r\n"), nil
+	}},
+}
+
+// Lookup 按名称查找转换
+func Lookup(name string) (Transform, bool) {
+	for _, t := range All {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Transform{}, false
+}
+
+// Apply 按顺序执行转换链
+func Apply(steps []string, text string) (string, error) {
+	for _, name := range steps {
+		t, ok := Lookup(name)
+		if !ok {
+			return "", fmt.Errorf("未知的转换: %s", name)
+		}
+		out, err := t.Apply(text)
+		if err != nil {
+			return "", fmt.Errorf("%s失败: %w", t.Label, err)
+		}
+		text = out
+	}
+	return text, nil
+}
+
+// Describe 返回转换链的显示名称，如 "去除首尾空白 → 转大写"
+func Describe(steps []string) string {
+	labels := make([]string, 0, len(steps))
+	for _, name := range steps {
+		if t, ok := Lookup(name); ok {
+			labels = append(labels, t.Label)
+		} else {
+			labels = append(labels, name)
+		}
+	}
+	return strings.Join(labels, " → ")
+}
+
+// titleCase 将每个单词首字母转为大写，其余小写
+func titleCase(s string) (string, error) {
+	var b strings.Builder
+	prevLetter := false
+	for _, r := range s {
+		if unicode.IsLetter(r) {
+			if prevLetter {
+				b.WriteRune(unicode.ToLower(r))
+			} else {
+				b.WriteRune(unicode.ToUpper(r))
+			}
+			prevLetter = true
+			continue
+		}
+		prevLetter = r == '\''
+		b.WriteRune(r)
+	}
+	return b.String(), nil
+}
+
+// jsonPretty 格式化 JSON
+func jsonPretty(s string) (string, error) {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(strings.TrimSpace(s)), "", "  "); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// jsonMinify 压缩 JSON
+func jsonMinify(s string) (string, error) {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(strings.TrimSpace(s))); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// base64Decode 解码 Base64，兼容 URL 安全编码和无填充编码
+func base64Decode(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	for _, enc := range []*base64.Encoding{
+		base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding,
+	} {
+		if data, err := enc.DecodeString(s); err == nil {
+			return string(data), nil
+		}
+	}
+	return "", fmt.Errorf("不是有效的 Base64 内容")
+}
+
+// stripTracking 移除网址中的 utm_* 等跟踪参数，非网址的文本原样返回
+func stripTracking(s string) (string, error) {
+	trimmed := strings.TrimSpace(s)
+	u, err := url.Parse(trimmed)
+	if err != nil || u.Host == "" || u.RawQuery == "" {
+		return s, nil
+	}
+
+	query := u.Query()
+	for key := range query {
+		if strings.HasPrefix(strings.ToLower(key), "utm_") || trackingParams[strings.ToLower(key)] {
+			query.Del(key)
+		}
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// shellEscape 用单引号包裹文本，使其可以安全地作为 Shell 参数
+func shellEscape(s string) (string, error) {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'", nil
+}
@@ -0,0 +1,64 @@
+package transform
+
+import "testing"
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name    string
+		steps   []string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"空转换链", nil, " a ", " a ", false},
+		{"去除空白后转大写", []string{"trim", "upper"}, "  hello ", "HELLO", false},
+		{"转小写", []string{"lower"}, "HeLLo", "hello", false},
+		{"首字母大写", []string{"title"}, "hello wORLD, it's ok", "Hello World, It's Ok", false},
+		{"JSON 格式化", []string{"json_pretty"}, `{"a":[1,2]}`, "{\n  \"a\": [\n    1,\n    2\n  ]\n}", false},
+		{"JSON 压缩", []string{"json_minify"}, "{\n  \"a\": 1\n}\n", `{"a":1}`, false},
+		{"无效 JSON", []string{"json_pretty"}, "{", "", true},
+		{"Base64 编码", []string{"base64_encode"}, "hi?", "aGk/", false},
+		{"Base64 解码", []string{"base64_decode"}, "aGk/", "hi?", false},
+		{"URL 安全 Base64 解码", []string{"base64_decode"}, "aGk_", "hi?", false},
+		{"无填充 Base64 解码", []string{"base64_decode"}, "aGk", "hi", false},
+		{"无效 Base64", []string{"base64_decode"}, "!!", "", true},
+		{"编码后解码", []string{"base64_encode", "base64_decode"}, "中文", "中文", false},
+		{"URL 编码", []string{"url_encode"}, "a b&c", "a+b%26c", false},
+		{"URL 解码", []string{"url_decode"}, "a+b%26c", "a b&c", false},
+		{"移除跟踪参数", []string{"strip_tracking"}, "https://example.com/p?id=1&utm_source=x&fbclid=y", "https://example.com/p?id=1", false},
+		{"跟踪参数不区分大小写", []string{"strip_tracking"}, "https://example.com/?UTM_Medium=x", "https://example.com/", false},
+		{"非网址原样返回", []string{"strip_tracking"}, "not a url?utm_source=x", "not a url?utm_source=x", false},
+		{"Shell 转义", []string{"shell_escape"}, "it's", `'it'\''s'`, false},
+		{"转为 LF", []string{"to_lf"}, "a\r\nb\n", "a\nb\n", false},
+		{"转为 CRLF", []string{"to_crlf"}, "a\r\nb\n", "a\r\nb\r\n", false},
+		{"未知转换", []string{"nope"}, "a", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply(tt.steps, tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Apply(%v, %q) 错误 = %v，期望出错 %v", tt.steps, tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Apply(%v, %q) = %q，期望 %q", tt.steps, tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	if got, want := Describe([]string{"trim", "upper", "nope"}), "去除首尾空白 → 转大写 → nope"; got != want {
+		t.Errorf("Describe = %q，期望 %q", got, want)
+	}
+}
+
+func TestAllNamesUnique(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, tr := range All {
+		if seen[tr.Name] {
+			t.Errorf("转换名称重复: %s", tr.Name)
+		}
+		seen[tr.Name] = true
+	}
+}
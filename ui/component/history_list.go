@@ -1,11 +1,18 @@
 package component
 
 import (
+	"clipboard/config"
+	"clipboard/filelist"
+	"clipboard/imageproc"
 	"clipboard/model"
+	"clipboard/stats"
+	"clipboard/thumbnail"
+	"clipboard/transform"
 	"fmt"
 	"image/color"
 	"log"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"fyne.io/fyne/v2"
@@ -22,6 +29,20 @@ type HistoryList struct {
 	onSelect   func(*model.ClipboardItem) // 选择回调
 	onFavorite func(string)               // 收藏回调
 	onDelete   func(string)               // 删除回调
+	cursor     int                        // 键盘操作的当前项，-1 表示没有
+	moving     bool                       // 移动当前项时忽略列表的选择回调
+	thumbs     map[string]struct{}        // 正在后台生成或生成失败的缩略图，只在界面线程访问
+
+	Presets           []config.TransformPreset                        // 转换预设
+	OnPasteAs         func(item *model.ClipboardItem, steps []string) // 转换后粘贴回调
+	OnCustomTransform func(item *model.ClipboardItem)                 // 自定义转换回调
+	Selection         *Selection                                      // 多选状态，为空时不支持多选
+	OnSplit           func(item *model.ClipboardItem)                 // 拆分多行文本回调
+	OnEdit            func(item *model.ClipboardItem)                 // 打开详情编辑回调
+	OnPreview         func(item *model.ClipboardItem)                 // 点击缩略图预览原图回调
+
+	OnImagePasteAs       func(item *model.ClipboardItem, opts imageproc.Options) // 图片转换后粘贴回调
+	OnCustomImageConvert func(item *model.ClipboardItem)                         // 自定义图片转换回调
 }
 
 // NewHistoryList 创建历史记录列表（保持原初始化逻辑）
@@ -36,6 +57,8 @@ func NewHistoryList(
 		onSelect:   onSelect,
 		onFavorite: onFavorite,
 		onDelete:   onDelete,
+		cursor:     -1,
+		thumbs:     make(map[string]struct{}),
 	}
 
 	list.List = widget.NewList(
@@ -51,12 +74,23 @@ func NewHistoryList(
 	)
 
 	list.OnSelected = func(i widget.ListItemID) {
+		if list.moving {
+			return
+		}
+		// 多选模式下点击切换选中状态，不写入剪贴板
+		if i >= 0 && i < len(list.items) && list.Selection != nil && list.Selection.Active {
+			list.Selection.Toggle(list.items[i].ID)
+			list.Unselect(i)
+			list.RefreshItem(i)
+			return
+		}
 		if i >= 0 && i < len(list.items) && list.onSelect != nil {
 			selectedItem := list.items[i]
 			list.onSelect(selectedItem) // 先触发复制逻辑
 			// 延迟100ms清除焦点（核心修改：避免打断剪贴板写入）
 			time.AfterFunc(100*time.Millisecond, func() {
 				fyne.Do(func() {
+					list.cursor = -1
 					list.Unselect(i) // 取消选中状态
 					// 清除画布焦点
 					if canvas := fyne.CurrentApp().Driver().CanvasForObject(list); canvas != nil {
@@ -71,12 +105,80 @@ func NewHistoryList(
 	return list
 }
 
-// UpdateItems 禁用增量更新，强制通过重建实现刷新
+// thumbnail 返回已生成的缩略图路径；缺失时在后台生成，完成后刷新该项所在行
+func (l *HistoryList) thumbnail(item *model.ClipboardItem) string {
+	if path, ok := thumbnail.Cached(item.ImagePath); ok {
+		return path
+	}
+	if _, seen := l.thumbs[item.ImagePath]; seen {
+		return ""
+	}
+
+	imagePath, id := item.ImagePath, item.ID
+	l.thumbs[imagePath] = struct{}{}
+	go func() {
+		_, err := thumbnail.Ensure(imagePath)
+		fyne.Do(func() {
+			if err != nil {
+				// 保留记录，避免每次刷新都重新解码
+				log.Printf("加载缩略图失败: %v", err)
+				return
+			}
+			delete(l.thumbs, imagePath)
+			for i, it := range l.items {
+				if it.ID == id {
+					l.RefreshItem(i)
+					break
+				}
+			}
+		})
+	}()
+	return ""
+}
+
+// UpdateItems 替换列表数据并清除当前项
 func (l *HistoryList) UpdateItems(items []*model.ClipboardItem) {
 	l.items = items
+	l.cursor = -1
+	l.UnselectAll()
 	l.Refresh()
 }
 
+// MoveCursor 将键盘操作的当前项移动 delta 行，没有当前项时从第一项开始
+func (l *HistoryList) MoveCursor(delta int) {
+	if l.cursor < 0 {
+		l.SetCursor(0)
+		return
+	}
+	l.SetCursor(l.cursor + delta)
+}
+
+// SetCursor 将第 i 项设为当前项并高亮（超出范围时取最近的项），不会写入剪贴板
+func (l *HistoryList) SetCursor(i int) {
+	if len(l.items) == 0 {
+		l.cursor = -1
+		l.UnselectAll()
+		return
+	}
+	l.cursor = max(0, min(i, len(l.items)-1))
+	l.moving = true
+	l.Select(l.cursor)
+	l.moving = false
+}
+
+// Cursor 返回当前项的位置，没有时返回 -1
+func (l *HistoryList) Cursor() int {
+	return l.cursor
+}
+
+// CursorItem 返回当前项，没有时返回 nil
+func (l *HistoryList) CursorItem() *model.ClipboardItem {
+	if l.cursor < 0 || l.cursor >= len(l.items) {
+		return nil
+	}
+	return l.items[l.cursor]
+}
+
 // 创建列表项控件（保持原逻辑）
 func (l *HistoryList) createItemWidget() fyne.CanvasObject {
 	content := widget.NewLabel("")
@@ -87,13 +189,21 @@ func (l *HistoryList) createItemWidget() fyne.CanvasObject {
 
 	favoriteBtn := widget.NewButtonWithIcon("", theme.ConfirmIcon(), func() {})
 	deleteBtn := widget.NewButtonWithIcon("", theme.CancelIcon(), func() {})
+	moreBtn := widget.NewButtonWithIcon("", theme.MoreHorizontalIcon(), func() {})
 
 	favoriteBtn.Importance = widget.LowImportance
 	deleteBtn.Importance = widget.LowImportance
+	moreBtn.Importance = widget.LowImportance
+
+	typeIcon := widget.NewIcon(theme.DocumentIcon())
+	selectIcon := widget.NewIcon(theme.CheckButtonIcon())
+	selectIcon.Hide()
+	thumb := NewThumbnail()
+	thumb.Hide()
 
 	mainContent := container.NewVBox(content, timestamp)
-	buttons := container.NewHBox(favoriteBtn, deleteBtn)
-	item := container.NewBorder(nil, nil, nil, buttons, mainContent)
+	buttons := container.NewHBox(moreBtn, favoriteBtn, deleteBtn)
+	item := container.NewBorder(nil, nil, container.NewHBox(selectIcon, typeIcon, thumb), buttons, mainContent)
 
 	return container.NewVBox(item, canvas.NewLine(color.Gray{Y: 200}))
 }
@@ -110,7 +220,7 @@ func (l *HistoryList) updateItemWidget(i int, o fyne.CanvasObject) {
 
 	// 找到内容容器
 	for _, obj := range box.Objects {
-		if container, ok := obj.(*fyne.Container); ok && container.Layout != nil && len(container.Objects) == 2 {
+		if container, ok := obj.(*fyne.Container); ok && container.Layout != nil && len(container.Objects) == 3 {
 			itemContainer = container
 			break
 		}
@@ -122,12 +232,18 @@ func (l *HistoryList) updateItemWidget(i int, o fyne.CanvasObject) {
 	}
 
 	mainContent := itemContainer.Objects[0].(*fyne.Container)
-	buttons := itemContainer.Objects[1].(*fyne.Container)
+	icons := itemContainer.Objects[1].(*fyne.Container)
+	buttons := itemContainer.Objects[2].(*fyne.Container)
+
+	selectIcon := icons.Objects[0].(*widget.Icon)
+	typeIcon := icons.Objects[1].(*widget.Icon)
+	thumb := icons.Objects[2].(*Thumbnail)
 
 	contentLabel := mainContent.Objects[0].(*widget.Label)
 	timeLabel := mainContent.Objects[1].(*widget.Label)
-	favoriteBtn := buttons.Objects[0].(*widget.Button)
-	deleteBtn := buttons.Objects[1].(*widget.Button)
+	moreBtn := buttons.Objects[0].(*widget.Button)
+	favoriteBtn := buttons.Objects[1].(*widget.Button)
+	deleteBtn := buttons.Objects[2].(*widget.Button)
 
 	// 准备内容文本
 	var contentText string
@@ -139,22 +255,101 @@ func (l *HistoryList) updateItemWidget(i int, o fyne.CanvasObject) {
 		}
 		contentText = content
 	case model.TypeImage:
-		contentText = "[图片内容] " + filepath.Base(item.ImagePath)
+		contentText = "[图片] " + filepath.Base(item.ImagePath)
+		if item.Width > 0 {
+			contentText = fmt.Sprintf("[图片] %d×%d %s %s", item.Width, item.Height,
+				strings.ToUpper(item.Format), stats.FormatBytes(item.ByteSize))
+		}
 	case model.TypeFile:
+		entries := filelist.Entries(item)
+		var names []string
+		for _, entry := range entries {
+			names = append(names, filepath.Base(entry.Path))
+		}
+		content := []rune(strings.Join(names, ", "))
+		if len(content) > 24 {
+			content = append(content[:24], []rune("...")...)
+		}
+		contentText = "[文件] " + string(content)
+		if len(entries) > 1 {
+			contentText += fmt.Sprintf("（共 %d 个）", len(entries))
+		}
+
+		// 标记原文件已丢失的项
+		if missing, recoverable := filelist.Missing(item); missing > 0 {
+			if recoverable == missing {
+				contentText = "[原文件已丢失，将粘贴快照] " + contentText
+			} else {
+				contentText = fmt.Sprintf("[%d 个原文件已丢失] ", missing) + contentText
+			}
+		}
+	case model.TypeSnippet:
 		content := item.Content
 		if len(content) > 15 {
 			content = content[:15] + "..."
 		}
-		contentText = "[文件] " + content
+		contentText = "[片段] " + content
+	}
+
+	// 敏感内容只显示掩码
+	if item.Sensitive {
+		contentText = "[敏感内容] ••••••••"
 	}
 
-	// 准备时间文本
+	// 标记来自 PRIMARY 选区的内容
+	if item.Source == model.SourcePrimary {
+		contentText = "[选区] " + contentText
+	}
+
+	// 准备时间文本（附带标签与过期提示）
 	timeText := formatTime(item.Timestamp)
+	if item.PasteCount > 0 {
+		timeText += fmt.Sprintf("  已粘贴 %d 次", item.PasteCount)
+	}
+	for _, tag := range item.Tags {
+		timeText += "  #" + tag
+	}
+	if item.ExpiresAt != nil {
+		timeText += "  " + item.ExpiresAt.Format("15:04") + " 过期"
+	}
+
+	// 图片显示缩略图，缩略图缺失时在后台生成，完成前显示类型图标
+	var thumbPath string
+	if item.Type == model.TypeImage && item.ImagePath != "" {
+		thumbPath = l.thumbnail(item)
+	}
 
 	// 主线程更新UI
 	fyne.Do(func() {
 		contentLabel.SetText(contentText)
 		timeLabel.SetText(timeText)
+		typeIcon.SetResource(itemIcon(item))
+
+		if thumbPath != "" {
+			thumb.SetFile(thumbPath)
+			thumb.OnTapped = func() {
+				if l.OnPreview != nil {
+					l.OnPreview(item)
+				}
+			}
+			thumb.Show()
+			typeIcon.Hide()
+		} else {
+			thumb.Hide()
+			typeIcon.Show()
+		}
+
+		// 多选模式下显示选中状态
+		if l.Selection != nil && l.Selection.Active {
+			if l.Selection.Has(item.ID) {
+				selectIcon.SetResource(theme.CheckButtonCheckedIcon())
+			} else {
+				selectIcon.SetResource(theme.CheckButtonIcon())
+			}
+			selectIcon.Show()
+		} else {
+			selectIcon.Hide()
+		}
 
 		// 设置收藏状态图标
 		if item.IsFavorite {
@@ -176,6 +371,21 @@ func (l *HistoryList) updateItemWidget(i int, o fyne.CanvasObject) {
 			}
 		}
 
+		// 转换菜单只对文本和片段生效
+		if (item.Type == model.TypeText || item.Type == model.TypeSnippet) && l.OnPasteAs != nil {
+			moreBtn.Show()
+			moreBtn.OnTapped = func() {
+				l.showPasteAsMenu(item, moreBtn)
+			}
+		} else if item.Type == model.TypeImage && l.OnImagePasteAs != nil {
+			moreBtn.Show()
+			moreBtn.OnTapped = func() {
+				l.showImageMenu(item, moreBtn)
+			}
+		} else {
+			moreBtn.Hide()
+		}
+
 		// 收藏项高亮
 		if item.IsFavorite {
 			var background *canvas.Rectangle
@@ -204,6 +414,7 @@ func (l *HistoryList) updateItemWidget(i int, o fyne.CanvasObject) {
 		// 强制刷新控件
 		contentLabel.Refresh()
 		timeLabel.Refresh()
+		moreBtn.Refresh()
 		favoriteBtn.Refresh()
 		deleteBtn.Refresh()
 		itemContainer.Refresh()
@@ -211,6 +422,128 @@ func (l *HistoryList) updateItemWidget(i int, o fyne.CanvasObject) {
 	})
 }
 
+// showPasteAsMenu 在按钮下方弹出"转换后粘贴"菜单
+func (l *HistoryList) showPasteAsMenu(item *model.ClipboardItem, anchor fyne.CanvasObject) {
+	var items []*fyne.MenuItem
+	for _, preset := range l.Presets {
+		steps := preset.Steps
+		items = append(items, fyne.NewMenuItem("预设: "+preset.Name, func() {
+			l.OnPasteAs(item, steps)
+		}))
+	}
+	if len(items) > 0 {
+		items = append(items, fyne.NewMenuItemSeparator())
+	}
+
+	single := fyne.NewMenuItem("转换后粘贴", nil)
+	for _, t := range transform.All {
+		name := t.Name
+		single.ChildMenu = appendMenuItem(single.ChildMenu, fyne.NewMenuItem(t.Label, func() {
+			l.OnPasteAs(item, []string{name})
+		}))
+	}
+	items = append(items, single)
+
+	if l.OnCustomTransform != nil {
+		items = append(items, fyne.NewMenuItem("自定义转换...", func() {
+			l.OnCustomTransform(item)
+		}))
+	}
+
+	if l.OnEdit != nil {
+		items = append(items, fyne.NewMenuItemSeparator(), fyne.NewMenuItem("详情与编辑...", func() {
+			l.OnEdit(item)
+		}))
+	}
+
+	if l.OnSplit != nil && item.Type == model.TypeText && strings.Contains(strings.TrimSpace(item.Content), "\n") {
+		items = append(items, fyne.NewMenuItemSeparator(), fyne.NewMenuItem("按行拆分为多项", func() {
+			l.OnSplit(item)
+		}))
+	}
+
+	showMenuBelow(fyne.NewMenu("", items...), anchor)
+}
+
+// showImageMenu 在按钮下方弹出图片转换菜单
+func (l *HistoryList) showImageMenu(item *model.ClipboardItem, anchor fyne.CanvasObject) {
+	var items []*fyne.MenuItem
+	for _, o := range imageQuickOptions {
+		opts := o.opts
+		items = append(items, fyne.NewMenuItem(o.label, func() {
+			l.OnImagePasteAs(item, opts)
+		}))
+	}
+
+	if l.OnCustomImageConvert != nil {
+		items = append(items, fyne.NewMenuItemSeparator(), fyne.NewMenuItem("自定义转换...", func() {
+			l.OnCustomImageConvert(item)
+		}))
+	}
+	if l.OnPreview != nil {
+		items = append(items, fyne.NewMenuItem("预览原图", func() {
+			l.OnPreview(item)
+		}))
+	}
+
+	showMenuBelow(fyne.NewMenu("", items...), anchor)
+}
+
+// appendMenuItem 向子菜单追加项，子菜单为空时创建
+func appendMenuItem(menu *fyne.Menu, item *fyne.MenuItem) *fyne.Menu {
+	if menu == nil {
+		return fyne.NewMenu("", item)
+	}
+	menu.Items = append(menu.Items, item)
+	return menu
+}
+
+// showMenuBelow 在控件下方弹出菜单
+func showMenuBelow(menu *fyne.Menu, anchor fyne.CanvasObject) {
+	driver := fyne.CurrentApp().Driver()
+	c := driver.CanvasForObject(anchor)
+	if c == nil {
+		return
+	}
+	pos := driver.AbsolutePositionForObject(anchor).Add(fyne.NewPos(0, anchor.Size().Height))
+	widget.ShowPopUpMenuAtPosition(menu, c, pos)
+}
+
+// itemIcon 根据内容类型和文本细分类型选择图标
+func itemIcon(item *model.ClipboardItem) fyne.Resource {
+	switch item.Type {
+	case model.TypeImage:
+		return theme.MediaPhotoIcon()
+	case model.TypeFile:
+		return theme.FolderIcon()
+	case model.TypeSnippet:
+		return theme.DocumentCreateIcon()
+	}
+
+	switch item.Subtype {
+	case model.SubtypeURL:
+		return theme.MailAttachmentIcon()
+	case model.SubtypeEmail:
+		return theme.MailComposeIcon()
+	case model.SubtypePhone:
+		return theme.AccountIcon()
+	case model.SubtypePath:
+		return theme.FileIcon()
+	case model.SubtypeColor:
+		return theme.ColorPaletteIcon()
+	case model.SubtypeJSON, model.SubtypeXML:
+		return theme.FileTextIcon()
+	case model.SubtypeSQL:
+		return theme.StorageIcon()
+	case model.SubtypeShell:
+		return theme.ComputerIcon()
+	case model.SubtypeCode:
+		return theme.FileApplicationIcon()
+	default:
+		return theme.DocumentIcon()
+	}
+}
+
 // 格式化时间显示（保持原逻辑）
 func formatTime(t time.Time) string {
 	now := time.Now()
@@ -0,0 +1,118 @@
+package component
+
+import (
+	"clipboard/hotkey"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+// HotkeyCapture 快捷键录制按钮：点击后按下组合键即可设置，Esc 取消
+type HotkeyCapture struct {
+	widget.Button
+	combo     hotkey.Combo
+	recording bool
+	modifier  fyne.KeyModifier   // 录制中已按下的修饰键
+	OnChanged func(hotkey.Combo) // 录制到新组合时回调
+}
+
+// NewHotkeyCapture 创建快捷键录制按钮
+func NewHotkeyCapture(combo hotkey.Combo) *HotkeyCapture {
+	c := &HotkeyCapture{combo: combo}
+	c.ExtendBaseWidget(c)
+	c.updateText()
+	return c
+}
+
+// Combo 返回当前组合，未设置时为空组合
+func (c *HotkeyCapture) Combo() hotkey.Combo {
+	return c.combo
+}
+
+// SetCombo 设置组合（不触发回调）
+func (c *HotkeyCapture) SetCombo(combo hotkey.Combo) {
+	c.combo = combo
+	c.recording = false
+	c.updateText()
+}
+
+// Tapped 点击后开始录制
+func (c *HotkeyCapture) Tapped(*fyne.PointEvent) {
+	c.recording = true
+	c.modifier = 0
+	c.updateText()
+	if canvas := fyne.CurrentApp().Driver().CanvasForObject(c); canvas != nil {
+		canvas.Focus(c)
+	}
+}
+
+// FocusLost 失去焦点时取消录制
+func (c *HotkeyCapture) FocusLost() {
+	c.Button.FocusLost()
+	if c.recording {
+		c.recording = false
+		c.updateText()
+	}
+}
+
+// KeyDown 录制修饰键与按键
+func (c *HotkeyCapture) KeyDown(ev *fyne.KeyEvent) {
+	if !c.recording {
+		return
+	}
+	if modifier := modifierOf(ev.Name); modifier != 0 {
+		c.modifier |= modifier
+		return
+	}
+
+	c.recording = false
+	if ev.Name != fyne.KeyEscape || c.modifier != 0 {
+		c.combo = hotkey.Combo{Modifier: c.modifier, Key: ev.Name}
+		if c.OnChanged != nil {
+			c.OnChanged(c.combo)
+		}
+	}
+	c.updateText()
+}
+
+// KeyUp 松开修饰键
+func (c *HotkeyCapture) KeyUp(ev *fyne.KeyEvent) {
+	c.modifier &^= modifierOf(ev.Name)
+}
+
+// TypedKey 录制中不触发按钮
+func (c *HotkeyCapture) TypedKey(ev *fyne.KeyEvent) {
+	if !c.recording {
+		c.Button.TypedKey(ev)
+	}
+}
+
+// TypedShortcut 录制中吞掉组合键，避免触发窗口快捷键
+func (c *HotkeyCapture) TypedShortcut(fyne.Shortcut) {}
+
+// updateText 按状态更新按钮文字
+func (c *HotkeyCapture) updateText() {
+	switch {
+	case c.recording:
+		c.SetText("请按下快捷键（Esc 取消）...")
+	case c.combo.IsZero():
+		c.SetText("未设置")
+	default:
+		c.SetText(c.combo.String())
+	}
+}
+
+// modifierOf 修饰键对应的修饰位，非修饰键返回 0
+func modifierOf(name fyne.KeyName) fyne.KeyModifier {
+	switch name {
+	case desktop.KeyControlLeft, desktop.KeyControlRight:
+		return fyne.KeyModifierControl
+	case desktop.KeyAltLeft, desktop.KeyAltRight:
+		return fyne.KeyModifierAlt
+	case desktop.KeyShiftLeft, desktop.KeyShiftRight:
+		return fyne.KeyModifierShift
+	case desktop.KeySuperLeft, desktop.KeySuperRight:
+		return fyne.KeyModifierSuper
+	}
+	return 0
+}
@@ -0,0 +1,88 @@
+package component
+
+import (
+	"clipboard/imageproc"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// imageFormatOptions 图片输出格式选项
+var imageFormatOptions = []struct {
+	label  string
+	format string
+}{
+	{"保持原格式", imageproc.FormatKeep},
+	{"PNG", imageproc.FormatPNG},
+	{"JPEG", imageproc.FormatJPEG},
+	{"GIF", imageproc.FormatGIF},
+}
+
+// imageQuickOptions 图片菜单中的常用转换
+var imageQuickOptions = []struct {
+	label string
+	opts  imageproc.Options
+}{
+	{"转为 PNG", imageproc.Options{Format: imageproc.FormatPNG}},
+	{"转为 JPEG", imageproc.Options{Format: imageproc.FormatJPEG}},
+	{"转为 GIF", imageproc.Options{Format: imageproc.FormatGIF}},
+	{"缩放到 1920 像素以内", imageproc.Options{MaxDimension: 1920}},
+	{"压缩到 1 MB 以内（JPEG）", imageproc.Options{Format: imageproc.FormatJPEG, TargetBytes: 1 << 20}},
+	{"去除元数据", imageproc.Options{StripMetadata: true}},
+}
+
+// ShowImageConvertDialog 弹出图片转换选项对话框
+func ShowImageConvertDialog(window fyne.Window, onConvert func(opts imageproc.Options)) {
+	var formatLabels []string
+	for _, o := range imageFormatOptions {
+		formatLabels = append(formatLabels, o.label)
+	}
+	formatSelect := widget.NewSelect(formatLabels, nil)
+	formatSelect.SetSelected(formatLabels[0])
+
+	maxDimension := widget.NewEntry()
+	maxDimension.SetPlaceHolder("不限制")
+	targetSize := widget.NewEntry()
+	targetSize.SetPlaceHolder("不限制，例如 1024")
+	quality := widget.NewEntry()
+	quality.SetText(strconv.Itoa(imageproc.DefaultQuality))
+	strip := widget.NewCheck("去除元数据（EXIF 等）", nil)
+	strip.SetChecked(true)
+
+	d := dialog.NewForm("转换图片后粘贴", "粘贴", "取消", []*widget.FormItem{
+		widget.NewFormItem("格式", formatSelect),
+		widget.NewFormItem("最长边（像素）", maxDimension),
+		widget.NewFormItem("目标大小（KB）", targetSize),
+		widget.NewFormItem("JPEG 质量", quality),
+		widget.NewFormItem("", strip),
+	}, func(ok bool) {
+		if !ok || onConvert == nil {
+			return
+		}
+
+		opts := imageproc.Options{StripMetadata: strip.Checked}
+		for _, o := range imageFormatOptions {
+			if o.label == formatSelect.Selected {
+				opts.Format = o.format
+			}
+		}
+		opts.MaxDimension = parsePositive(maxDimension.Text)
+		opts.TargetBytes = int64(parsePositive(targetSize.Text)) * 1024
+		opts.Quality = min(parsePositive(quality.Text), 100)
+		onConvert(opts)
+	}, window)
+	d.Resize(fyne.NewSize(380, 0))
+	d.Show()
+}
+
+// parsePositive 解析正整数，无效或为空时返回 0
+func parsePositive(text string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(text))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
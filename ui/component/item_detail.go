@@ -0,0 +1,158 @@
+package component
+
+import (
+	"clipboard/model"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ItemDetailActions 详情对话框的操作回调
+type ItemDetailActions struct {
+	OnSave   func(content string) error          // 保存编辑后的内容
+	OnRevert func(revision model.Revision) error // 恢复到历史版本
+}
+
+// ShowItemDetail 显示项详情：可编辑内容，并查看、对比、恢复历史版本
+func ShowItemDetail(window fyne.Window, item *model.ClipboardItem, revisions []model.Revision, actions ItemDetailActions) {
+	var d dialog.Dialog
+
+	editor := widget.NewMultiLineEntry()
+	editor.SetText(item.Content)
+	editor.Wrapping = fyne.TextWrapWord
+	editor.SetMinRowsVisible(8)
+
+	info := widget.NewLabel(fmt.Sprintf("创建于 %s，最后修改于 %s",
+		item.CreatedAt.Format("2006-01-02 15:04"), item.UpdatedAt.Format("2006-01-02 15:04")))
+	info.TextStyle = fyne.TextStyle{Italic: true}
+
+	saveBtn := widget.NewButton("保存", func() {
+		if editor.Text == item.Content {
+			d.Hide()
+			return
+		}
+		if err := actions.OnSave(editor.Text); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		d.Hide()
+	})
+	saveBtn.Importance = widget.HighImportance
+
+	// 历史版本列表
+	revisionList := widget.NewList(
+		func() int { return len(revisions) },
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, nil,
+				container.NewHBox(widget.NewButton("对比", nil), widget.NewButton("恢复", nil)),
+				widget.NewLabel(""))
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			rev := revisions[i]
+			row := o.(*fyne.Container)
+			label := row.Objects[0].(*widget.Label)
+			buttons := row.Objects[1].(*fyne.Container)
+
+			preview := []rune(strings.ReplaceAll(rev.Content, "\n", " "))
+			if len(preview) > 24 {
+				preview = append(preview[:24], []rune("...")...)
+			}
+			label.SetText(rev.CreatedAt.Format("01-02 15:04") + "  " + string(preview))
+
+			buttons.Objects[0].(*widget.Button).OnTapped = func() {
+				showDiff(window, rev.Content, item.Content)
+			}
+			buttons.Objects[1].(*widget.Button).OnTapped = func() {
+				dialog.ShowConfirm("恢复版本", "将内容恢复为该版本？当前内容会保存为新的历史版本。", func(ok bool) {
+					if !ok {
+						return
+					}
+					if err := actions.OnRevert(rev); err != nil {
+						dialog.ShowError(err, window)
+						return
+					}
+					d.Hide()
+				}, window)
+			}
+		},
+	)
+
+	var history fyne.CanvasObject = widget.NewLabel("暂无历史版本")
+	if len(revisions) > 0 {
+		history = revisionList
+	}
+
+	content := container.NewBorder(
+		container.NewVBox(info, editor, container.NewHBox(saveBtn), widget.NewSeparator(),
+			widget.NewLabel(fmt.Sprintf("历史版本（%d）:", len(revisions)))),
+		nil, nil, nil,
+		history,
+	)
+
+	d = dialog.NewCustom("详情", "关闭", content, window)
+	d.Resize(fyne.NewSize(560, 560))
+	d.Show()
+}
+
+// showDiff 显示两段文本的逐行差异
+func showDiff(window fyne.Window, oldText, newText string) {
+	var b strings.Builder
+	for _, line := range diffLines(oldText, newText) {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	grid := widget.NewTextGridFromString(strings.TrimSuffix(b.String(), "\n"))
+	d := dialog.NewCustom("版本对比（- 历史版本  + 当前内容）", "关闭", container.NewScroll(grid), window)
+	d.Resize(fyne.NewSize(520, 400))
+	d.Show()
+}
+
+// diffLines 基于最长公共子序列计算逐行差异，行首为 "  "、"- " 或 "+ "
+func diffLines(oldText, newText string) []string {
+	a := strings.Split(oldText, "\n")
+	b := strings.Split(newText, "\n")
+
+	// lcs[i][j] 为 a[i:] 与 b[j:] 的最长公共子序列长度
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out []string
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			out = append(out, "  "+a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, "- "+a[i])
+			i++
+		default:
+			out = append(out, "+ "+b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		out = append(out, "- "+a[i])
+	}
+	for ; j < len(b); j++ {
+		out = append(out, "+ "+b[j])
+	}
+	return out
+}
@@ -0,0 +1,92 @@
+package component
+
+import (
+	"clipboard/transform"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// separatorOptions 合并时可选的分隔符
+var separatorOptions = []struct {
+	label string
+	value string
+}{
+	{"换行", "\n"},
+	{"逗号", ", "},
+	{"空格", " "},
+	{"制表符", "\t"},
+	{"自定义", ""},
+}
+
+// quoteOptions 合并时可选的引号
+var quoteOptions = []struct {
+	label string
+	value string
+}{
+	{"不加引号", ""},
+	{"双引号", `"`},
+	{"单引号", "'"},
+}
+
+// ShowJoinDialog 弹出合并选项对话框
+func ShowJoinDialog(window fyne.Window, count int, onJoin func(opts transform.JoinOptions, keepOriginals bool)) {
+	var sepLabels []string
+	for _, o := range separatorOptions {
+		sepLabels = append(sepLabels, o.label)
+	}
+	customSep := widget.NewEntry()
+	customSep.SetPlaceHolder("自定义分隔符")
+	customSep.Disable()
+	sepSelect := widget.NewSelect(sepLabels, func(label string) {
+		if label == "自定义" {
+			customSep.Enable()
+		} else {
+			customSep.Disable()
+		}
+	})
+	sepSelect.SetSelected(sepLabels[0])
+
+	var quoteLabels []string
+	for _, o := range quoteOptions {
+		quoteLabels = append(quoteLabels, o.label)
+	}
+	quoteSelect := widget.NewSelect(quoteLabels, nil)
+	quoteSelect.SetSelected(quoteLabels[0])
+
+	numbered := widget.NewCheck("添加序号", nil)
+	keep := widget.NewCheck("保留原项", nil)
+	keep.SetChecked(true)
+
+	d := dialog.NewForm(fmt.Sprintf("合并 %d 项", count), "合并", "取消", []*widget.FormItem{
+		widget.NewFormItem("分隔符", sepSelect),
+		widget.NewFormItem("", customSep),
+		widget.NewFormItem("引号", quoteSelect),
+		widget.NewFormItem("", numbered),
+		widget.NewFormItem("", keep),
+	}, func(ok bool) {
+		if !ok || onJoin == nil {
+			return
+		}
+
+		opts := transform.JoinOptions{Numbered: numbered.Checked}
+		for _, o := range separatorOptions {
+			if o.label == sepSelect.Selected {
+				opts.Separator = o.value
+			}
+		}
+		if sepSelect.Selected == "自定义" {
+			opts.Separator = customSep.Text
+		}
+		for _, o := range quoteOptions {
+			if o.label == quoteSelect.Selected {
+				opts.Quote = o.value
+			}
+		}
+		onJoin(opts, keep.Checked)
+	}, window)
+	d.Resize(fyne.NewSize(360, 0))
+	d.Show()
+}
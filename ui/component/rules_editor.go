@@ -0,0 +1,128 @@
+package component
+
+import (
+	"clipboard/config"
+	"clipboard/model"
+	"clipboard/rules"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ruleTypeOptions 规则测试可选的内容类型
+var ruleTypeOptions = []struct {
+	itemType model.ItemType
+	label    string
+}{
+	{model.TypeText, "文本"},
+	{model.TypeFile, "文件"},
+	{model.TypeImage, "图片"},
+}
+
+// ShowRulesEditor 显示捕获规则编辑对话框
+// 规则以JSON编辑，并可用样例内容测试命中的规则
+func ShowRulesEditor(window fyne.Window, current []config.CaptureRule, onConfirm func([]config.CaptureRule)) {
+	data, err := json.MarshalIndent(current, "", "  ")
+	if err != nil || len(current) == 0 {
+		data = []byte("[]")
+	}
+
+	rulesEntry := widget.NewMultiLineEntry()
+	rulesEntry.SetText(string(data))
+	rulesEntry.SetMinRowsVisible(12)
+
+	// 规则测试区域
+	var typeLabels []string
+	for _, t := range ruleTypeOptions {
+		typeLabels = append(typeLabels, t.label)
+	}
+	sampleType := widget.NewSelect(typeLabels, nil)
+	sampleType.SetSelected(typeLabels[0])
+
+	sampleEntry := widget.NewMultiLineEntry()
+	sampleEntry.SetPlaceHolder("输入样例文本；图片类型请输入字节数")
+	sampleEntry.SetMinRowsVisible(3)
+
+	resultLabel := widget.NewLabel("")
+	resultLabel.Wrapping = fyne.TextWrapWord
+
+	testBtn := widget.NewButton("测试", func() {
+		parsed, err := parseRules(rulesEntry.Text)
+		if err != nil {
+			resultLabel.SetText("规则解析失败: " + err.Error())
+			return
+		}
+
+		in := rules.Input{Text: sampleEntry.Text}
+		for _, t := range ruleTypeOptions {
+			if t.label == sampleType.Selected {
+				in.Type = t.itemType
+			}
+		}
+		if in.Type == model.TypeImage {
+			in.Text = ""
+			in.ImageSize, _ = strconv.ParseInt(strings.TrimSpace(sampleEntry.Text), 10, 64)
+		}
+
+		matches, err := rules.Test(parsed, in)
+		var lines []string
+		if err != nil {
+			lines = append(lines, "警告: "+err.Error())
+		}
+		if len(matches) == 0 {
+			lines = append(lines, "未命中任何规则，将正常保存")
+		}
+		for _, m := range matches {
+			lines = append(lines, "命中: "+m.String())
+		}
+		resultLabel.SetText(strings.Join(lines, "\n"))
+	})
+
+	content := container.NewVBox(
+		widget.NewLabel("捕获规则（JSON，按顺序求值）:"),
+		rulesEntry,
+		widget.NewSeparator(),
+		widget.NewLabel("规则测试:"),
+		container.NewBorder(nil, nil, sampleType, testBtn),
+		sampleEntry,
+		resultLabel,
+	)
+
+	d := dialog.NewCustomConfirm("捕获规则", "确定", "取消", content, func(ok bool) {
+		if !ok {
+			return
+		}
+		parsed, err := parseRules(rulesEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		if _, err := rules.NewEngine(parsed); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		if onConfirm != nil {
+			onConfirm(parsed)
+		}
+	}, window)
+	d.Resize(fyne.NewSize(560, 560))
+	d.Show()
+}
+
+// parseRules 解析JSON格式的规则列表
+func parseRules(text string) ([]config.CaptureRule, error) {
+	var parsed []config.CaptureRule
+	if strings.TrimSpace(text) == "" {
+		return parsed, nil
+	}
+	if err := json.Unmarshal([]byte(text), &parsed); err != nil {
+		return nil, fmt.Errorf("规则格式错误: %w", err)
+	}
+	return parsed, nil
+}
@@ -2,27 +2,30 @@ package component
 
 import (
 	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
 	"fyne.io/fyne/v2/widget"
 	"log"
 )
 
 // SearchBar 搜索框组件
 type SearchBar struct {
-	*widget.Entry
-	onSearch func(string) // 搜索回调函数
+	widget.Entry
+	onSearch   func(string)             // 搜索回调函数
+	OnNavigate func(delta int)          // 上下方向键回调，用于移动列表的当前项
+	OnShortcut func(fyne.Shortcut) bool // 窗口快捷键回调，返回 true 表示已处理
 }
 
 // NewSearchBar 创建搜索框
 func NewSearchBar(onSearch func(string)) *SearchBar {
 	search := &SearchBar{
-		Entry:    widget.NewEntry(),
 		onSearch: onSearch,
 	}
+	search.ExtendBaseWidget(search)
 
-	search.SetPlaceHolder("搜索剪贴板历史...")
+	search.SetPlaceHolder("搜索剪贴板历史（支持 type:url、lang:go、format:png、width>1920 过滤）...")
 	search.OnChanged = func(text string) {
-		log.Printf("搜索关键词变更: %s，触发重建", text)
-		search.onSearch(text) // 回调由windows.go的rebuildFullUI实现
+		log.Printf("搜索关键词变更: %s", text)
+		search.onSearch(text) // 回调由windows.go的performSearch实现
 	}
 
 	return search
@@ -37,3 +40,26 @@ func (s *SearchBar) handleSearch(text string) {
 		})
 	}
 }
+
+// TypedKey 上下方向键交给列表，其他按键按普通输入处理
+func (s *SearchBar) TypedKey(ev *fyne.KeyEvent) {
+	if s.OnNavigate != nil {
+		switch ev.Name {
+		case fyne.KeyUp:
+			s.OnNavigate(-1)
+			return
+		case fyne.KeyDown:
+			s.OnNavigate(1)
+			return
+		}
+	}
+	s.Entry.TypedKey(ev)
+}
+
+// TypedShortcut 复制、粘贴、撤销等编辑快捷键由输入框处理，其他组合键先交给窗口
+func (s *SearchBar) TypedShortcut(shortcut fyne.Shortcut) {
+	if _, ok := shortcut.(*desktop.CustomShortcut); ok && s.OnShortcut != nil && s.OnShortcut(shortcut) {
+		return
+	}
+	s.Entry.TypedShortcut(shortcut)
+}
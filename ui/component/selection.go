@@ -0,0 +1,60 @@
+package component
+
+// Selection 列表多选状态，按选中顺序记录项ID
+// 由窗口持有，列表刷新后选中状态不会丢失
+type Selection struct {
+	Active   bool     // 是否处于多选模式
+	ids      []string // 按选中顺序排列的ID
+	OnChange func()   // 选中项变化回调
+}
+
+// NewSelection 创建多选状态
+func NewSelection() *Selection {
+	return &Selection{}
+}
+
+// Toggle 切换项的选中状态
+func (s *Selection) Toggle(id string) {
+	for i, existing := range s.ids {
+		if existing == id {
+			s.ids = append(s.ids[:i], s.ids[i+1:]...)
+			s.changed()
+			return
+		}
+	}
+	s.ids = append(s.ids, id)
+	s.changed()
+}
+
+// Has 判断项是否被选中
+func (s *Selection) Has(id string) bool {
+	for _, existing := range s.ids {
+		if existing == id {
+			return true
+		}
+	}
+	return false
+}
+
+// IDs 返回按选中顺序排列的ID
+func (s *Selection) IDs() []string {
+	return append([]string(nil), s.ids...)
+}
+
+// Len 返回选中数量
+func (s *Selection) Len() int {
+	return len(s.ids)
+}
+
+// Clear 清空选中项
+func (s *Selection) Clear() {
+	s.ids = nil
+	s.changed()
+}
+
+// changed 触发变化回调
+func (s *Selection) changed() {
+	if s.OnChange != nil {
+		s.OnChange()
+	}
+}
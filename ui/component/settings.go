@@ -2,7 +2,10 @@ package component
 
 import (
 	"clipboard/config"
+	"clipboard/hotkey"
+	"clipboard/httpapi"
 	"errors"
+	"fmt"
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
@@ -11,6 +14,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 )
 
 // SettingsPanel 应用设置面板
@@ -19,21 +23,70 @@ type SettingsPanel struct {
 	window          fyne.Window
 	storageType     *widget.Select
 	maxItemsEntry   *widget.Entry
+	primaryMode     *widget.Select
+	hotkeyCapture   *HotkeyCapture
+	hotkeyAction    *widget.Select
+	hotkeyStatus    *widget.Label
+	shortcuts       map[config.ShortcutAction]string // 编辑中的窗口内快捷键
+	captureRules    []config.CaptureRule             // 编辑中的捕获规则
+	sensitiveCheck  *widget.Check
+	persistPause    *widget.Check
+	sensitiveExpire *widget.Entry
+	dedupeCheck     *widget.Check
+	dedupeDistance  *widget.Entry
+	snapshotCheck   *widget.Check
+	snapshotMaxSize *widget.Entry
+	trayCheck       *widget.Check
+	trayRecent      *widget.Entry
+	apiCheck        *widget.Check
+	apiAddr         *widget.Entry
+	apiToken        *widget.Entry
+	rulesLabel      *widget.Label
 	customPathCheck *widget.Check
 	jsonPathEntry   *widget.Entry
 	browseBtn       *widget.Button
 	saveBtn         *widget.Button
 	mysqlSettings   *fyne.Container // MySQL设置容器
 	jsonSettings    *fyne.Container // JSON设置容器
-	saveCallback    func(*config.StorageConfig)
+	saveCallback    func(*config.AppConfig)
+}
+
+// primaryModeLabels PRIMARY 选区处理方式的显示名称
+var primaryModeLabels = []struct {
+	mode  config.PrimaryMode
+	label string
+}{
+	{config.PrimaryModeIgnore, "忽略"},
+	{config.PrimaryModeCapture, "记录到历史"},
+	{config.PrimaryModeSync, "与剪贴板同步"},
+}
+
+// hotkeyActionLabels 全局快捷键动作的显示名称
+var hotkeyActionLabels = []struct {
+	action config.HotkeyAction
+	label  string
+}{
+	{config.HotkeyActionPicker, "打开快速粘贴窗口"},
+	{config.HotkeyActionWindow, "显示主窗口"},
+}
+
+// editingKeys 输入框的编辑快捷键（Ctrl+键），注册为全局快捷键会使所有程序无法使用
+var editingKeys = map[fyne.KeyName]string{
+	fyne.KeyA: "全选",
+	fyne.KeyC: "复制",
+	fyne.KeyV: "粘贴",
+	fyne.KeyX: "剪切",
+	fyne.KeyY: "重做",
+	fyne.KeyZ: "撤销",
 }
 
 // NewSettingsPanel 创建设置面板
-func NewSettingsPanel(window fyne.Window, cfg *config.StorageConfig, saveCallback func(*config.StorageConfig)) *SettingsPanel {
+func NewSettingsPanel(window fyne.Window, appCfg *config.AppConfig, saveCallback func(*config.AppConfig)) *SettingsPanel {
 	p := &SettingsPanel{
 		window:       window,
 		saveCallback: saveCallback,
 	}
+	cfg := &appCfg.Storage
 
 	// 初始化存储类型选择器
 	p.storageType = widget.NewSelect(
@@ -45,6 +98,111 @@ func NewSettingsPanel(window fyne.Window, cfg *config.StorageConfig, saveCallbac
 	p.maxItemsEntry = widget.NewEntry()
 	p.maxItemsEntry.SetText(strconv.Itoa(cfg.MaxItems))
 
+	// 初始化PRIMARY选区处理方式选择器
+	var primaryOptions []string
+	for _, m := range primaryModeLabels {
+		primaryOptions = append(primaryOptions, m.label)
+	}
+	p.primaryMode = widget.NewSelect(primaryOptions, nil)
+	p.primaryMode.SetSelected(primaryOptions[0])
+	for _, m := range primaryModeLabels {
+		if m.mode == appCfg.PrimaryMode {
+			p.primaryMode.SetSelected(m.label)
+		}
+	}
+
+	// 初始化全局快捷键控件
+	combo, err := hotkey.Parse(appCfg.Hotkey)
+	p.hotkeyStatus = widget.NewLabel("")
+	p.hotkeyStatus.Wrapping = fyne.TextWrapWord
+	if err != nil && appCfg.Hotkey != "" {
+		p.hotkeyStatus.SetText(fmt.Sprintf("配置中的快捷键 %q 无效: %v", appCfg.Hotkey, err))
+	}
+	p.hotkeyCapture = NewHotkeyCapture(combo)
+	accepted := combo
+	p.hotkeyCapture.OnChanged = func(captured hotkey.Combo) {
+		if p.checkHotkey(captured, appCfg.Hotkey) {
+			accepted = captured
+		} else {
+			p.hotkeyCapture.SetCombo(accepted)
+		}
+	}
+	clearHotkeyBtn := widget.NewButton("清除", func() {
+		accepted = hotkey.Combo{}
+		p.hotkeyCapture.SetCombo(accepted)
+		p.hotkeyStatus.SetText("")
+	})
+	var hotkeyActionOptions []string
+	for _, a := range hotkeyActionLabels {
+		hotkeyActionOptions = append(hotkeyActionOptions, a.label)
+	}
+	p.hotkeyAction = widget.NewSelect(hotkeyActionOptions, nil)
+	p.hotkeyAction.SetSelected(hotkeyActionOptions[0])
+	for _, a := range hotkeyActionLabels {
+		if a.action == appCfg.HotkeyAction {
+			p.hotkeyAction.SetSelected(a.label)
+		}
+	}
+
+	// 初始化窗口内快捷键入口
+	p.shortcuts = appCfg.Shortcuts
+	shortcutsBtn := widget.NewButton("编辑窗口快捷键...", func() {
+		ShowShortcutEditor(p.window, p.shortcuts, p.hotkeyCapture.Combo().String(), func(updated map[config.ShortcutAction]string) {
+			p.shortcuts = updated
+		})
+	})
+
+	// 初始化捕获规则入口
+	p.captureRules = appCfg.CaptureRules
+	p.rulesLabel = widget.NewLabel("")
+	p.updateRulesLabel()
+	rulesBtn := widget.NewButton("编辑捕获规则...", func() {
+		ShowRulesEditor(p.window, p.captureRules, func(updated []config.CaptureRule) {
+			p.captureRules = updated
+			p.updateRulesLabel()
+		})
+	})
+
+	// 初始化敏感内容检测控件
+	p.sensitiveCheck = widget.NewCheck("检测密码、令牌等敏感内容并自动过期", nil)
+	p.sensitiveCheck.SetChecked(appCfg.Sensitive.Enabled)
+	p.sensitiveExpire = widget.NewEntry()
+	p.sensitiveExpire.SetText(strconv.Itoa(appCfg.Sensitive.ExpireMinutes))
+
+	// 初始化相似图片去重控件
+	p.dedupeCheck = widget.NewCheck("合并相似图片（同一截图重新编码后不重复记录）", nil)
+	p.dedupeCheck.SetChecked(appCfg.ImageDedupe.Enabled)
+	p.dedupeDistance = widget.NewEntry()
+	p.dedupeDistance.SetText(strconv.Itoa(appCfg.ImageDedupe.MaxDistance))
+
+	// 初始化文件快照控件
+	p.snapshotCheck = widget.NewCheck("为复制的文件保存快照（原文件删除后仍可粘贴）", nil)
+	p.snapshotCheck.SetChecked(appCfg.FileSnapshot.Enabled)
+	p.snapshotMaxSize = widget.NewEntry()
+	p.snapshotMaxSize.SetText(strconv.FormatInt(appCfg.FileSnapshot.MaxFileSize>>20, 10))
+
+	// 初始化系统托盘控件
+	p.trayCheck = widget.NewCheck("显示托盘图标，关闭窗口时最小化到托盘（关闭此项需重启生效）", nil)
+	p.trayCheck.SetChecked(appCfg.Tray.Enabled)
+	p.trayRecent = widget.NewEntry()
+	p.trayRecent.SetText(strconv.Itoa(appCfg.Tray.RecentItems))
+
+	// 初始化 HTTP 接口控件
+	p.apiCheck = widget.NewCheck("启用本地 HTTP 接口（供编辑器插件、启动器、浏览器扩展使用）", nil)
+	p.apiCheck.SetChecked(appCfg.HTTPAPI.Enabled)
+	p.apiAddr = widget.NewEntry()
+	p.apiAddr.SetText(appCfg.HTTPAPI.Addr)
+	p.apiToken = widget.NewEntry()
+	p.apiToken.SetText(appCfg.HTTPAPI.Token)
+	p.apiToken.SetPlaceHolder("保存时自动生成")
+	tokenBtn := widget.NewButton("重新生成", func() {
+		p.apiToken.SetText(httpapi.GenerateToken())
+	})
+
+	// 初始化暂停状态持久化选项
+	p.persistPause = widget.NewCheck("重启后保持捕获暂停状态", nil)
+	p.persistPause.SetChecked(appCfg.CapturePause.Persist)
+
 	// 初始化JSON存储相关控件
 	p.customPathCheck = widget.NewCheck("使用自定义路径", func(checked bool) {
 		p.jsonPathEntry.Disable()
@@ -106,7 +264,7 @@ func NewSettingsPanel(window fyne.Window, cfg *config.StorageConfig, saveCallbac
 		container.NewHBox(widget.NewLabel("数据库:"), mysqlDBEntry),
 	)
 
-	// 设置保存按钮（回调由windows.go实现重建）
+	// 设置保存按钮（回调由windows.go刷新界面）
 	p.saveBtn = widget.NewButton("保存设置", func() {
 		// 解析最大项目数
 		maxItems, err := strconv.Atoi(p.maxItemsEntry.Text)
@@ -133,8 +291,9 @@ func NewSettingsPanel(window fyne.Window, cfg *config.StorageConfig, saveCallbac
 			os.MkdirAll(jsonPath, 0755)
 		}
 
-		// 创建配置对象
-		newCfg := &config.StorageConfig{
+		// 创建配置对象（未在面板中出现的字段沿用原配置）
+		newCfg := *appCfg
+		newCfg.Storage = config.StorageConfig{
 			Type:       config.StorageType(p.storageType.Selected),
 			JSONPath:   jsonPath,
 			CustomPath: p.customPathCheck.Checked,
@@ -147,13 +306,81 @@ func NewSettingsPanel(window fyne.Window, cfg *config.StorageConfig, saveCallbac
 			},
 			MaxItems: maxItems,
 		}
+		for _, m := range primaryModeLabels {
+			if m.label == p.primaryMode.Selected {
+				newCfg.PrimaryMode = m.mode
+			}
+		}
+		newCfg.CaptureRules = p.captureRules
+		newCfg.Hotkey = p.hotkeyCapture.Combo().String()
+		newCfg.Shortcuts = p.shortcuts
+		for _, a := range hotkeyActionLabels {
+			if a.label == p.hotkeyAction.Selected {
+				newCfg.HotkeyAction = a.action
+			}
+		}
 
-		// 调用回调（由windows.go触发重建）
+		// 解析敏感内容保留时长
+		expireMinutes, err := strconv.Atoi(p.sensitiveExpire.Text)
+		if err != nil || expireMinutes <= 0 {
+			expireMinutes = 60
+		}
+		newCfg.Sensitive = config.SensitiveConfig{
+			Enabled:       p.sensitiveCheck.Checked,
+			ExpireMinutes: expireMinutes,
+		}
+		newCfg.CapturePause.Persist = p.persistPause.Checked
+
+		// 解析相似图片的最大汉明距离
+		distance, err := strconv.Atoi(p.dedupeDistance.Text)
+		if err != nil || distance < 0 || distance > 64 {
+			distance = config.DefaultImageDedupeDistance
+		}
+		newCfg.ImageDedupe = config.ImageDedupeConfig{
+			Enabled:     p.dedupeCheck.Checked,
+			MaxDistance: distance,
+		}
+
+		// 解析快照文件大小上限（MB）
+		snapshotMB, err := strconv.ParseInt(p.snapshotMaxSize.Text, 10, 64)
+		if err != nil || snapshotMB <= 0 {
+			snapshotMB = 10
+		}
+		newCfg.FileSnapshot = config.FileSnapshotConfig{
+			Enabled:     p.snapshotCheck.Checked,
+			MaxFileSize: snapshotMB << 20,
+		}
+
+		// 解析托盘最近项数量
+		trayRecent, err := strconv.Atoi(p.trayRecent.Text)
+		if err != nil || trayRecent <= 0 {
+			trayRecent = 10
+		}
+		newCfg.Tray = config.TrayConfig{
+			Enabled:     p.trayCheck.Checked,
+			RecentItems: trayRecent,
+		}
+
+		// HTTP 接口：启用时必须有令牌
+		newCfg.HTTPAPI = config.HTTPAPIConfig{
+			Enabled: p.apiCheck.Checked,
+			Addr:    strings.TrimSpace(p.apiAddr.Text),
+			Token:   strings.TrimSpace(p.apiToken.Text),
+		}
+		if newCfg.HTTPAPI.Addr == "" {
+			newCfg.HTTPAPI.Addr = config.DefaultHTTPAPIAddr
+		}
+		if newCfg.HTTPAPI.Enabled && newCfg.HTTPAPI.Token == "" {
+			newCfg.HTTPAPI.Token = httpapi.GenerateToken()
+			p.apiToken.SetText(newCfg.HTTPAPI.Token)
+		}
+
+		// 调用回调（由windows.go刷新界面）
 		if p.saveCallback != nil {
-			p.saveCallback(newCfg)
+			p.saveCallback(&newCfg)
 		}
 
-		dialog.ShowInformation("设置已保存", "您的设置已成功保存（已触发UI重建）", p.window)
+		dialog.ShowInformation("设置已保存", "您的设置已成功保存", p.window)
 	})
 
 	// 设置存储类型变更回调
@@ -175,6 +402,31 @@ func NewSettingsPanel(window fyne.Window, cfg *config.StorageConfig, saveCallbac
 		widget.NewSeparator(),
 		widget.NewLabel("存储设置:"),
 		container.NewVBox(p.jsonSettings, p.mysqlSettings),
+		widget.NewSeparator(),
+		widget.NewLabel("PRIMARY选区（鼠标选中文本）:"),
+		p.primaryMode,
+		widget.NewSeparator(),
+		container.NewBorder(nil, nil, widget.NewLabel("全局快捷键:"), clearHotkeyBtn, p.hotkeyCapture),
+		container.NewHBox(widget.NewLabel("快捷键动作:"), p.hotkeyAction),
+		p.hotkeyStatus,
+		container.NewHBox(shortcutsBtn),
+		widget.NewSeparator(),
+		container.NewHBox(p.rulesLabel, rulesBtn),
+		widget.NewSeparator(),
+		p.sensitiveCheck,
+		container.NewHBox(widget.NewLabel("敏感内容保留分钟数:"), p.sensitiveExpire),
+		p.dedupeCheck,
+		container.NewHBox(widget.NewLabel("相似度阈值（汉明距离 0-64）:"), p.dedupeDistance),
+		p.snapshotCheck,
+		container.NewHBox(widget.NewLabel("快照单个文件上限（MB）:"), p.snapshotMaxSize),
+		p.persistPause,
+		widget.NewSeparator(),
+		p.trayCheck,
+		container.NewHBox(widget.NewLabel("托盘菜单显示最近项数:"), p.trayRecent),
+		widget.NewSeparator(),
+		p.apiCheck,
+		container.NewBorder(nil, nil, widget.NewLabel("监听地址:"), nil, p.apiAddr),
+		container.NewBorder(nil, nil, widget.NewLabel("访问令牌:"), tokenBtn, p.apiToken),
 		layout.NewSpacer(),
 		p.saveBtn,
 	)
@@ -182,6 +434,41 @@ func NewSettingsPanel(window fyne.Window, cfg *config.StorageConfig, saveCallbac
 	return p
 }
 
+// checkHotkey 检查录制到的快捷键，无效或与窗口内快捷键冲突时返回 false；
+// 被其他程序占用时仍可保存，但只在窗口内生效
+func (p *SettingsPanel) checkHotkey(combo hotkey.Combo, saved string) bool {
+	if err := combo.Validate(); err != nil {
+		p.hotkeyStatus.SetText(fmt.Sprintf("%s 不能用作快捷键: %v", combo, err))
+		return false
+	}
+	if name, ok := editingKeys[combo.Key]; ok && combo.Modifier == fyne.KeyModifierShortcutDefault {
+		p.hotkeyStatus.SetText(fmt.Sprintf("%s 是%s快捷键，注册为全局快捷键后其他程序将无法使用", combo, name))
+		return false
+	}
+	if name := shortcutConflict(p.shortcuts, combo, ""); name != "" {
+		p.hotkeyStatus.SetText(fmt.Sprintf("%s 与窗口快捷键「%s」冲突", combo, name))
+		return false
+	}
+
+	p.hotkeyStatus.SetText("")
+	// 当前已注册的快捷键由本程序占用，无需再检查
+	if combo.String() == saved {
+		return true
+	}
+	switch err := hotkey.Available(combo); {
+	case errors.Is(err, hotkey.ErrConflict):
+		p.hotkeyStatus.SetText(fmt.Sprintf("%s 已被其他程序占用，保存后只在本窗口内生效", combo))
+	case err != nil:
+		p.hotkeyStatus.SetText(fmt.Sprintf("无法注册全局快捷键（%v），保存后只在本窗口内生效", err))
+	}
+	return true
+}
+
+// updateRulesLabel 更新捕获规则数量提示
+func (p *SettingsPanel) updateRulesLabel() {
+	p.rulesLabel.SetText(fmt.Sprintf("捕获规则: %d 条", len(p.captureRules)))
+}
+
 // updateStorageSettingsVisibility 根据存储类型更新设置面板可见性
 func (p *SettingsPanel) updateStorageSettingsVisibility(storageType string) {
 	if p.jsonSettings == nil || p.mysqlSettings == nil {
@@ -0,0 +1,100 @@
+package component
+
+import (
+	"clipboard/config"
+	"clipboard/hotkey"
+	"fmt"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"maps"
+)
+
+// shortcutLabels 窗口内快捷键动作的显示名称，按编辑器中的顺序排列
+var shortcutLabels = []struct {
+	action config.ShortcutAction
+	label  string
+}{
+	{config.ShortcutFocusSearch, "聚焦搜索框"},
+	{config.ShortcutCopySelected, "复制当前项"},
+	{config.ShortcutDeleteSelected, "删除当前项"},
+	{config.ShortcutToggleFavorite, "收藏/取消收藏当前项"},
+	{config.ShortcutNextTab, "下一个标签页"},
+	{config.ShortcutPrevTab, "上一个标签页"},
+	{config.ShortcutOpenSettings, "打开设置"},
+	{config.ShortcutUndo, "撤销删除或收藏"},
+	{config.ShortcutQueueNext, "载入粘贴队列下一项"},
+}
+
+// shortcutConflict 返回 bindings 中除 except 外绑定到 combo 的动作名称，没有时返回空
+func shortcutConflict(bindings map[config.ShortcutAction]string, combo hotkey.Combo, except config.ShortcutAction) string {
+	for _, s := range shortcutLabels {
+		if s.action != except && bindings[s.action] == combo.String() {
+			return s.label
+		}
+	}
+	return ""
+}
+
+// ShowShortcutEditor 显示窗口内快捷键编辑对话框，globalHotkey 为当前全局快捷键，用于冲突检查
+func ShowShortcutEditor(window fyne.Window, current map[config.ShortcutAction]string, globalHotkey string, onConfirm func(map[config.ShortcutAction]string)) {
+	bindings := maps.Clone(current)
+
+	status := widget.NewLabel("点击按钮后按下新的组合键；搜索框中的复制、粘贴、撤销仍用于编辑文字")
+	status.Wrapping = fyne.TextWrapWord
+
+	form := container.NewGridWithColumns(4)
+	for _, s := range shortcutLabels {
+		action := s.action
+		combo, _ := hotkey.Parse(bindings[action])
+		capture := NewHotkeyCapture(combo)
+		capture.OnChanged = func(captured hotkey.Combo) {
+			previous, _ := hotkey.Parse(bindings[action])
+			if err := captured.Validate(); err != nil {
+				status.SetText(fmt.Sprintf("%s 不能用作快捷键: %v", captured, err))
+				capture.SetCombo(previous)
+				return
+			}
+			if name := shortcutConflict(bindings, captured, action); name != "" {
+				status.SetText(fmt.Sprintf("%s 已绑定到「%s」", captured, name))
+				capture.SetCombo(previous)
+				return
+			}
+			if captured.String() == globalHotkey {
+				status.SetText(fmt.Sprintf("%s 已用作全局快捷键", captured))
+				capture.SetCombo(previous)
+				return
+			}
+			bindings[action] = captured.String()
+			status.SetText("")
+		}
+
+		clearBtn := widget.NewButton("清除", func() {
+			bindings[action] = ""
+			capture.SetCombo(hotkey.Combo{})
+		})
+		defaultBtn := widget.NewButton("默认", func() {
+			def, _ := hotkey.Parse(config.DefaultShortcuts[action])
+			if name := shortcutConflict(bindings, def, action); name != "" {
+				status.SetText(fmt.Sprintf("默认快捷键 %s 已绑定到「%s」", def, name))
+				return
+			}
+			bindings[action] = def.String()
+			capture.SetCombo(def)
+		})
+		form.Add(widget.NewLabel(s.label))
+		form.Add(capture)
+		form.Add(clearBtn)
+		form.Add(defaultBtn)
+	}
+
+	content := container.NewBorder(nil, status, nil, nil, form)
+	d := dialog.NewCustomConfirm("窗口快捷键", "确定", "取消", content, func(ok bool) {
+		if ok && onConfirm != nil {
+			onConfirm(bindings)
+		}
+	}, window)
+	d.Resize(fyne.NewSize(560, 480))
+	d.Show()
+}
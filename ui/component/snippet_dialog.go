@@ -0,0 +1,68 @@
+package component
+
+import (
+	"errors"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ShowSnippetInputDialog 为片段中的 {{input:标签}} 占位符弹出输入对话框
+func ShowSnippetInputDialog(window fyne.Window, labels []string, onSubmit func(map[string]string)) {
+	entries := make([]*widget.Entry, len(labels))
+	formItems := make([]*widget.FormItem, len(labels))
+	for i, label := range labels {
+		entries[i] = widget.NewEntry()
+		formItems[i] = widget.NewFormItem(label, entries[i])
+	}
+
+	d := dialog.NewForm("填写片段内容", "粘贴", "取消", formItems, func(ok bool) {
+		if !ok {
+			return
+		}
+		inputs := make(map[string]string, len(labels))
+		for i, label := range labels {
+			inputs[label] = entries[i].Text
+		}
+		if onSubmit != nil {
+			onSubmit(inputs)
+		}
+	}, window)
+	d.Resize(fyne.NewSize(400, 0))
+	d.Show()
+
+	// 聚焦第一个输入框，方便直接键入
+	if len(entries) > 0 {
+		window.Canvas().Focus(entries[0])
+	}
+}
+
+// ShowSnippetEditor 弹出新建片段对话框
+func ShowSnippetEditor(window fyne.Window, onSave func(content string)) {
+	entry := widget.NewMultiLineEntry()
+	entry.SetPlaceHolder("例如：工单 {{input:工单号}} 于 {{date:2006-01-02}} 处理")
+	entry.SetMinRowsVisible(6)
+
+	help := widget.NewLabel("占位符：{{date:格式}}、{{clipboard}}、{{uuid}}、{{input:标签}}")
+	help.Wrapping = fyne.TextWrapWord
+
+	d := dialog.NewForm("新建片段", "保存", "取消", []*widget.FormItem{
+		widget.NewFormItem("内容", entry),
+		widget.NewFormItem("", help),
+	}, func(ok bool) {
+		if !ok {
+			return
+		}
+		if strings.TrimSpace(entry.Text) == "" {
+			dialog.ShowError(errors.New("片段内容不能为空"), window)
+			return
+		}
+		if onSave != nil {
+			onSave(entry.Text)
+		}
+	}, window)
+	d.Resize(fyne.NewSize(480, 320))
+	d.Show()
+}
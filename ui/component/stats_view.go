@@ -0,0 +1,177 @@
+package component
+
+import (
+	"clipboard/stats"
+	"fmt"
+	"strconv"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	chartHeight    = 100 // 柱状图最大高度
+	columnWidth    = 14  // 柱宽
+	barMaxWidth    = 240 // 横向条最大宽度
+	chartLabelSize = 9   // 坐标文字大小
+)
+
+// typeLabels 类型统计的中文名称
+var typeLabels = map[string]string{
+	"text":    "文本",
+	"image":   "图片",
+	"file":    "文件",
+	"snippet": "片段",
+}
+
+// NewStatsView 根据统计结果创建统计面板
+func NewStatsView(report *stats.Report) fyne.CanvasObject {
+	summary := widget.NewLabel(fmt.Sprintf("共 %d 项，收藏 %d 项，累计粘贴 %d 次，占用 %s（数据 %s，图片 %d 个 %s）",
+		report.TotalItems, report.Favorites, report.TotalPastes,
+		stats.FormatBytes(report.Storage.TotalBytes),
+		stats.FormatBytes(report.Storage.DataBytes),
+		report.Storage.ImageFiles, stats.FormatBytes(report.Storage.ImageBytes)))
+	summary.Wrapping = fyne.TextWrapWord
+
+	// 最近每天
+	dayLabels := make([]string, len(report.PerDay))
+	dayValues := make([]int, len(report.PerDay))
+	for i, d := range report.PerDay {
+		if date, err := time.Parse("2006-01-02", d.Date); err == nil {
+			dayLabels[i] = date.Format("01/02")
+		}
+		dayValues[i] = d.Count
+	}
+
+	// 每小时
+	hourLabels := make([]string, 24)
+	for h := range hourLabels {
+		if h%3 == 0 {
+			hourLabels[h] = strconv.Itoa(h)
+		}
+	}
+
+	// 类型分布
+	var typeNames []string
+	var typeValues []int
+	for key, count := range report.Types {
+		typeNames = append(typeNames, typeLabel(key))
+		typeValues = append(typeValues, count)
+	}
+	sortBars(typeNames, typeValues)
+
+	// 常用域名
+	var domainNames []string
+	var domainValues []int
+	for _, d := range report.TopDomains {
+		domainNames = append(domainNames, d.Domain)
+		domainValues = append(domainValues, d.Count)
+	}
+
+	// 最常粘贴
+	var pastedNames []string
+	var pastedValues []int
+	for _, p := range report.MostPasted {
+		pastedNames = append(pastedNames, p.Preview)
+		pastedValues = append(pastedValues, p.PasteCount)
+	}
+
+	return container.NewVBox(
+		summary,
+		section("最近 14 天", columnChart(dayLabels, dayValues)),
+		section("按小时分布", columnChart(hourLabels, report.PerHour[:])),
+		section("类型分布", barChart(typeNames, typeValues)),
+		section("常用域名", barChart(domainNames, domainValues)),
+		section("最常粘贴", barChart(pastedNames, pastedValues)),
+	)
+}
+
+// section 带标题的统计分区
+func section(title string, content fyne.CanvasObject) fyne.CanvasObject {
+	heading := widget.NewLabelWithStyle(title, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	return container.NewVBox(widget.NewSeparator(), heading, content)
+}
+
+// columnChart 用矩形绘制纵向柱状图
+func columnChart(labels []string, values []int) fyne.CanvasObject {
+	maxValue := maxOf(values)
+	columns := container.NewHBox()
+	for i, v := range values {
+		bar := canvas.NewRectangle(theme.Color(theme.ColorNamePrimary))
+		height := float32(0)
+		if maxValue > 0 {
+			height = chartHeight * float32(v) / float32(maxValue)
+		}
+		bar.SetMinSize(fyne.NewSize(columnWidth, height))
+
+		value := chartText("")
+		if v > 0 {
+			value.Text = strconv.Itoa(v)
+		}
+		// 柱子靠底部对齐
+		columns.Add(container.NewVBox(layout.NewSpacer(), value, bar, chartText(labels[i])))
+	}
+	return columns
+}
+
+// barChart 用矩形绘制横向条形图
+func barChart(names []string, values []int) fyne.CanvasObject {
+	if len(values) == 0 {
+		return widget.NewLabel("暂无数据")
+	}
+
+	maxValue := maxOf(values)
+	rows := container.New(layout.NewFormLayout())
+	for i, v := range values {
+		bar := canvas.NewRectangle(theme.Color(theme.ColorNamePrimary))
+		bar.SetMinSize(fyne.NewSize(barMaxWidth*float32(v)/float32(maxValue), 12))
+		rows.Add(widget.NewLabel(names[i]))
+		rows.Add(container.NewHBox(container.NewCenter(bar), widget.NewLabel(strconv.Itoa(v))))
+	}
+	return rows
+}
+
+// chartText 创建图表坐标文字
+func chartText(text string) *canvas.Text {
+	t := canvas.NewText(text, theme.Color(theme.ColorNameForeground))
+	t.TextSize = chartLabelSize
+	t.Alignment = fyne.TextAlignCenter
+	return t
+}
+
+// typeLabel 返回类型统计键的中文名称，如 "text/url" -> "文本/url"
+func typeLabel(key string) string {
+	for prefix, label := range typeLabels {
+		if key == prefix {
+			return label
+		}
+		if len(key) > len(prefix) && key[:len(prefix)+1] == prefix+"/" {
+			return label + key[len(prefix):]
+		}
+	}
+	return key
+}
+
+// sortBars 按数值从大到小排序条形图数据
+func sortBars(names []string, values []int) {
+	for i := 1; i < len(values); i++ {
+		for j := i; j > 0 && (values[j] > values[j-1] || values[j] == values[j-1] && names[j] < names[j-1]); j-- {
+			values[j], values[j-1] = values[j-1], values[j]
+			names[j], names[j-1] = names[j-1], names[j]
+		}
+	}
+}
+
+// maxOf 返回最大值
+func maxOf(values []int) int {
+	m := 0
+	for _, v := range values {
+		m = max(m, v)
+	}
+	return m
+}
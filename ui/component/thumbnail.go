@@ -0,0 +1,64 @@
+package component
+
+import (
+	"path/filepath"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// thumbnailSize 列表中缩略图的显示尺寸
+const thumbnailSize = 48
+
+// Thumbnail 可点击的图片缩略图
+type Thumbnail struct {
+	widget.BaseWidget
+	image    *canvas.Image
+	OnTapped func() // 点击回调
+}
+
+// NewThumbnail 创建缩略图控件
+func NewThumbnail() *Thumbnail {
+	image := canvas.NewImageFromResource(nil)
+	image.FillMode = canvas.ImageFillContain
+	image.SetMinSize(fyne.NewSize(thumbnailSize, thumbnailSize))
+
+	t := &Thumbnail{image: image}
+	t.ExtendBaseWidget(t)
+	return t
+}
+
+// SetFile 设置缩略图文件
+func (t *Thumbnail) SetFile(path string) {
+	if t.image.File == path {
+		return
+	}
+	t.image.File = path
+	t.image.Refresh()
+}
+
+// Tapped 点击时触发回调
+func (t *Thumbnail) Tapped(*fyne.PointEvent) {
+	if t.OnTapped != nil {
+		t.OnTapped()
+	}
+}
+
+// CreateRenderer 创建渲染器
+func (t *Thumbnail) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(t.image)
+}
+
+// ShowImagePreview 在应用内显示原图预览
+func ShowImagePreview(window fyne.Window, imagePath string) {
+	image := canvas.NewImageFromFile(imagePath)
+	image.FillMode = canvas.ImageFillContain
+	image.ScaleMode = canvas.ImageScaleSmooth
+
+	d := dialog.NewCustom(filepath.Base(imagePath), "关闭", container.NewStack(image), window)
+	d.Resize(fyne.NewSize(640, 480))
+	d.Show()
+}
@@ -0,0 +1,74 @@
+package component
+
+import (
+	"clipboard/transform"
+	"errors"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ShowTransformDialog 显示自定义转换链对话框
+// 按添加顺序组成转换链；填写预设名称时同时保存为预设
+func ShowTransformDialog(window fyne.Window, onApply func(steps []string, presetName string)) {
+	var steps []string
+
+	chainLabel := widget.NewLabel("（未添加转换）")
+	chainLabel.Wrapping = fyne.TextWrapWord
+	updateChain := func() {
+		if len(steps) == 0 {
+			chainLabel.SetText("（未添加转换）")
+			return
+		}
+		chainLabel.SetText(transform.Describe(steps))
+	}
+
+	var labels []string
+	for _, t := range transform.All {
+		labels = append(labels, t.Label)
+	}
+	stepSelect := widget.NewSelect(labels, nil)
+	stepSelect.PlaceHolder = "选择转换"
+
+	addBtn := widget.NewButton("添加", func() {
+		for _, t := range transform.All {
+			if t.Label == stepSelect.Selected {
+				steps = append(steps, t.Name)
+			}
+		}
+		updateChain()
+	})
+	clearBtn := widget.NewButton("清空", func() {
+		steps = nil
+		updateChain()
+	})
+
+	presetEntry := widget.NewEntry()
+	presetEntry.SetPlaceHolder("可选：保存为预设的名称")
+
+	content := container.NewVBox(
+		container.NewBorder(nil, nil, nil, container.NewHBox(addBtn, clearBtn), stepSelect),
+		widget.NewLabel("转换链:"),
+		chainLabel,
+		widget.NewSeparator(),
+		presetEntry,
+	)
+
+	d := dialog.NewCustomConfirm("自定义转换", "转换并粘贴", "取消", content, func(ok bool) {
+		if !ok {
+			return
+		}
+		if len(steps) == 0 {
+			dialog.ShowError(errors.New("请至少添加一个转换"), window)
+			return
+		}
+		if onApply != nil {
+			onApply(steps, strings.TrimSpace(presetEntry.Text))
+		}
+	}, window)
+	d.Resize(fyne.NewSize(420, 300))
+	d.Show()
+}
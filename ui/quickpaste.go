@@ -0,0 +1,313 @@
+package ui
+
+import (
+	"clipboard/filelist"
+	"clipboard/model"
+	"clipboard/snippet"
+	"clipboard/storage"
+	"clipboard/ui/component"
+	"fmt"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+	"log"
+	"strings"
+)
+
+// quickPasteMax 快速粘贴窗口最多显示的项数
+const quickPasteMax = 50
+
+// QuickPaste 快速粘贴窗口：无边框，打开时聚焦搜索框，完全用键盘操作
+//
+//	↑/↓      移动选中项
+//	Enter    粘贴选中项
+//	Shift+Enter  以纯文本粘贴（文件粘贴为路径文本）
+//	1-9      搜索框为空时粘贴对应序号的项（Alt+1-9 任何时候可用）
+//	Esc      关闭
+type QuickPaste struct {
+	window    fyne.Window
+	storage   storage.Storage
+	clipboard ClipboardSetter
+	entry     *pickerEntry
+	list      *widget.List
+	items     []*model.ClipboardItem
+	selected  int
+	selecting bool // 键盘移动选中项时忽略列表的点击回调
+}
+
+// NewQuickPaste 创建快速粘贴窗口（初始隐藏）
+func NewQuickPaste(app fyne.App, storage storage.Storage, setter ClipboardSetter) *QuickPaste {
+	var win fyne.Window
+	if drv, ok := app.Driver().(desktop.Driver); ok {
+		win = drv.CreateSplashWindow()
+	} else {
+		win = app.NewWindow("快速粘贴")
+	}
+	win.Resize(fyne.NewSize(480, 360))
+
+	q := &QuickPaste{
+		window:    win,
+		storage:   storage,
+		clipboard: setter,
+	}
+
+	q.entry = newPickerEntry()
+	q.entry.SetPlaceHolder("搜索后按 Enter 粘贴，Esc 关闭")
+	q.entry.OnChanged = func(text string) {
+		q.search(text)
+	}
+	q.entry.onKey = q.handleKey
+	q.entry.onShortcut = q.handleShortcut
+
+	q.list = widget.NewList(
+		func() int {
+			return len(q.items)
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			obj.(*widget.Label).SetText(quickPasteLabel(id, q.items[id]))
+		},
+	)
+	q.list.OnSelected = func(id widget.ListItemID) {
+		if q.selecting {
+			return
+		}
+		// 鼠标点击直接粘贴
+		q.selected = id
+		q.paste(false)
+	}
+
+	win.SetContent(container.NewBorder(q.entry, nil, nil, nil, q.list))
+	win.SetCloseIntercept(q.Hide)
+	return q
+}
+
+// SetStorage 设置保存后存储被重建时替换使用的存储
+func (q *QuickPaste) SetStorage(store storage.Storage) {
+	q.storage = store
+}
+
+// Show 清空搜索词并显示窗口
+func (q *QuickPaste) Show() {
+	q.entry.SetText("")
+	q.search("")
+	q.window.Show()
+	q.window.RequestFocus()
+	q.window.Canvas().Focus(q.entry)
+}
+
+// Hide 隐藏窗口
+func (q *QuickPaste) Hide() {
+	q.window.Hide()
+}
+
+// search 按关键词刷新列表，选中第一项
+func (q *QuickPaste) search(keyword string) {
+	items, err := q.storage.Search(keyword)
+	if err != nil {
+		log.Printf("快速粘贴搜索失败: %v", err)
+		items = nil
+	}
+	if len(items) > quickPasteMax {
+		items = items[:quickPasteMax]
+	}
+	q.items = items
+	q.list.Refresh()
+	q.selectIndex(0)
+}
+
+// selectIndex 移动选中项并滚动到可见位置
+func (q *QuickPaste) selectIndex(i int) {
+	if len(q.items) == 0 {
+		q.selected = 0
+		q.list.UnselectAll()
+		return
+	}
+	q.selected = max(0, min(i, len(q.items)-1))
+	q.selecting = true
+	q.list.Select(q.selected)
+	q.selecting = false
+}
+
+// handleKey 处理搜索框中的按键，返回 true 表示已处理
+func (q *QuickPaste) handleKey(ev *fyne.KeyEvent, shift bool) bool {
+	switch ev.Name {
+	case fyne.KeyUp:
+		q.selectIndex(q.selected - 1)
+	case fyne.KeyDown:
+		q.selectIndex(q.selected + 1)
+	case fyne.KeyPageUp:
+		q.selectIndex(q.selected - 10)
+	case fyne.KeyPageDown:
+		q.selectIndex(q.selected + 10)
+	case fyne.KeyReturn, fyne.KeyEnter:
+		q.paste(shift)
+	case fyne.KeyEscape:
+		q.Hide()
+	default:
+		// 搜索框为空时数字键直接选择
+		if q.entry.Text == "" {
+			if n, ok := digitKey(ev.Name); ok {
+				q.pasteIndex(n - 1)
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// handleShortcut 处理 Alt+1-9，返回 true 表示已处理
+func (q *QuickPaste) handleShortcut(shortcut fyne.Shortcut) bool {
+	custom, ok := shortcut.(*desktop.CustomShortcut)
+	if !ok || custom.Modifier != fyne.KeyModifierAlt {
+		return false
+	}
+	n, ok := digitKey(custom.KeyName)
+	if !ok {
+		return false
+	}
+	q.pasteIndex(n - 1)
+	return true
+}
+
+// pasteIndex 粘贴指定位置的项
+func (q *QuickPaste) pasteIndex(i int) {
+	if i < 0 || i >= len(q.items) {
+		return
+	}
+	q.selected = i
+	q.paste(false)
+}
+
+// paste 粘贴选中项后关闭窗口，plain 为 true 时以纯文本粘贴
+func (q *QuickPaste) paste(plain bool) {
+	if q.selected < 0 || q.selected >= len(q.items) {
+		return
+	}
+	item := q.items[q.selected]
+	if plain {
+		item = plainTextItem(item)
+	}
+
+	if item.Type != model.TypeSnippet {
+		q.finish(q.clipboard.SetContent(item))
+		return
+	}
+
+	labels := snippet.Inputs(item.Content)
+	if len(labels) == 0 {
+		q.finish(q.clipboard.SetSnippetContent(item, nil))
+		return
+	}
+	component.ShowSnippetInputDialog(q.window, labels, func(inputs map[string]string) {
+		q.finish(q.clipboard.SetSnippetContent(item, inputs))
+	})
+}
+
+// finish 粘贴成功后关闭窗口，失败时提示错误
+func (q *QuickPaste) finish(err error) {
+	if err != nil {
+		dialog.ShowError(err, q.window)
+		return
+	}
+	q.Hide()
+}
+
+// plainTextItem 返回以纯文本写回的副本：文件项写为路径文本，片段按原文写入，图片保持不变
+func plainTextItem(item *model.ClipboardItem) *model.ClipboardItem {
+	plain := *item
+	switch item.Type {
+	case model.TypeFile:
+		var paths []string
+		for _, entry := range filelist.Entries(item) {
+			paths = append(paths, entry.Path)
+		}
+		plain.Content = strings.Join(paths, "\n")
+	case model.TypeImage:
+		return item
+	}
+	plain.Type = model.TypeText
+	return &plain
+}
+
+// quickPasteLabel 列表行文本，前 9 项带序号
+func quickPasteLabel(i int, item *model.ClipboardItem) string {
+	text := strings.Join(strings.Fields(previewText(item)), " ")
+	switch item.Type {
+	case model.TypeFile:
+		text = "[文件] " + text
+	case model.TypeSnippet:
+		text = "[片段] " + text
+	}
+	if i < 9 {
+		return fmt.Sprintf("%d  %s", i+1, text)
+	}
+	return "   " + text
+}
+
+// digitKey 将 1-9 键转换为数字
+func digitKey(name fyne.KeyName) (int, bool) {
+	if len(name) == 1 && name[0] >= '1' && name[0] <= '9' {
+		return int(name[0] - '0'), true
+	}
+	return 0, false
+}
+
+// pickerEntry 快速粘贴的搜索框，拦截导航按键并记录 Shift 状态
+type pickerEntry struct {
+	widget.Entry
+	shift      bool
+	onKey      func(ev *fyne.KeyEvent, shift bool) bool
+	onShortcut func(fyne.Shortcut) bool
+}
+
+func newPickerEntry() *pickerEntry {
+	e := &pickerEntry{}
+	e.ExtendBaseWidget(e)
+	return e
+}
+
+// TypedKey 先交给快速粘贴处理，未处理的按键按普通输入处理
+func (e *pickerEntry) TypedKey(ev *fyne.KeyEvent) {
+	if e.onKey != nil && e.onKey(ev, e.shift) {
+		return
+	}
+	e.Entry.TypedKey(ev)
+}
+
+// TypedRune 数字键已被选择时不再作为输入
+func (e *pickerEntry) TypedRune(r rune) {
+	if e.Text == "" && r >= '1' && r <= '9' {
+		return
+	}
+	e.Entry.TypedRune(r)
+}
+
+// TypedShortcut 先交给快速粘贴处理 Alt+数字
+func (e *pickerEntry) TypedShortcut(shortcut fyne.Shortcut) {
+	if e.onShortcut != nil && e.onShortcut(shortcut) {
+		return
+	}
+	e.Entry.TypedShortcut(shortcut)
+}
+
+// KeyDown 记录 Shift 状态（Shift+Enter 不会产生快捷键事件）
+func (e *pickerEntry) KeyDown(ev *fyne.KeyEvent) {
+	if ev.Name == desktop.KeyShiftLeft || ev.Name == desktop.KeyShiftRight {
+		e.shift = true
+	}
+	e.Entry.KeyDown(ev)
+}
+
+// KeyUp 记录 Shift 状态
+func (e *pickerEntry) KeyUp(ev *fyne.KeyEvent) {
+	if ev.Name == desktop.KeyShiftLeft || ev.Name == desktop.KeyShiftRight {
+		e.shift = false
+	}
+	e.Entry.KeyUp(ev)
+}
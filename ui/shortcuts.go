@@ -0,0 +1,281 @@
+package ui
+
+import (
+	"clipboard/config"
+	"clipboard/hotkey"
+	"clipboard/model"
+	"clipboard/ui/component"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"log"
+	"slices"
+)
+
+// maxUndo 最多可撤销的操作数
+const maxUndo = 20
+
+// applyShortcuts 按配置重新绑定窗口内快捷键，无效的绑定跳过
+func (w *Window) applyShortcuts(bindings map[config.ShortcutAction]string) {
+	canvas := w.Canvas()
+	for _, shortcut := range w.shortcuts {
+		canvas.RemoveShortcut(shortcut)
+	}
+	w.shortcuts = nil
+	w.bindings = make(map[hotkey.Combo]config.ShortcutAction)
+
+	handlers := w.shortcutHandlers()
+	for action, text := range bindings {
+		handler, ok := handlers[action]
+		if !ok || text == "" {
+			continue
+		}
+		combo, err := hotkey.Parse(text)
+		if err != nil {
+			log.Printf("快捷键 %s 的绑定 %q 无效: %v", action, text, err)
+			continue
+		}
+
+		w.bindings[combo] = action
+		shortcut := canvasShortcut(combo)
+		canvas.AddShortcut(shortcut, func(fyne.Shortcut) {
+			handler()
+		})
+		w.shortcuts = append(w.shortcuts, shortcut)
+	}
+}
+
+// shortcutHandlers 各动作的处理函数
+func (w *Window) shortcutHandlers() map[config.ShortcutAction]func() {
+	return map[config.ShortcutAction]func(){
+		config.ShortcutFocusSearch:    w.focusSearch,
+		config.ShortcutCopySelected:   w.pasteCursorItem,
+		config.ShortcutDeleteSelected: w.deleteCursorItem,
+		config.ShortcutToggleFavorite: w.toggleCursorFavorite,
+		config.ShortcutNextTab:        func() { w.switchTab(1) },
+		config.ShortcutPrevTab:        func() { w.switchTab(-1) },
+		config.ShortcutOpenSettings:   w.openSettings,
+		config.ShortcutUndo:           w.undo,
+		config.ShortcutQueueNext:      w.advanceQueue,
+	}
+}
+
+// handleShortcut 处理搜索框转交的组合键，返回 true 表示已处理
+func (w *Window) handleShortcut(shortcut fyne.Shortcut) bool {
+	combo, ok := hotkey.FromShortcut(shortcut)
+	if !ok {
+		return false
+	}
+	if w.hotkeyFallback != nil && shortcut.ShortcutName() == w.hotkeyFallback.ShortcutName() {
+		w.hotkeyAction()
+		return true
+	}
+	action, ok := w.bindings[combo]
+	if !ok {
+		return false
+	}
+	w.shortcutHandlers()[action]()
+	return true
+}
+
+// handleTypedKey 没有控件获得焦点时，用方向键与回车操作当前列表
+func (w *Window) handleTypedKey(ev *fyne.KeyEvent) {
+	switch ev.Name {
+	case fyne.KeyUp:
+		w.moveCursor(-1)
+	case fyne.KeyDown:
+		w.moveCursor(1)
+	case fyne.KeyReturn, fyne.KeyEnter:
+		w.pasteCursorItem()
+	}
+}
+
+// canvasShortcut 转换为窗口收到的快捷键类型：Ctrl+C 等组合由驱动识别为标准快捷键，
+// 按名称匹配时需要注册为同一类型
+func canvasShortcut(combo hotkey.Combo) fyne.Shortcut {
+	if combo.Modifier == fyne.KeyModifierShortcutDefault {
+		switch combo.Key {
+		case fyne.KeyZ:
+			return &fyne.ShortcutUndo{}
+		case fyne.KeyY:
+			return &fyne.ShortcutRedo{}
+		case fyne.KeyV:
+			return &fyne.ShortcutPaste{}
+		case fyne.KeyC:
+			return &fyne.ShortcutCopy{}
+		case fyne.KeyX:
+			return &fyne.ShortcutCut{}
+		case fyne.KeyA:
+			return &fyne.ShortcutSelectAll{}
+		}
+	}
+	return combo.Shortcut()
+}
+
+// currentList 当前标签页的列表，统计页与设置页返回 nil
+func (w *Window) currentList() *component.HistoryList {
+	if w.contentTabs == nil {
+		return nil
+	}
+	switch w.contentTabs.SelectedIndex() {
+	case 0:
+		return w.historyList
+	case 1:
+		return w.favoriteList
+	case 2:
+		return w.snippetList
+	}
+	return nil
+}
+
+// moveCursor 移动当前列表的键盘当前项
+func (w *Window) moveCursor(delta int) {
+	if list := w.currentList(); list != nil {
+		list.MoveCursor(delta)
+	}
+}
+
+// cursorItem 返回当前列表的当前项，没有当前项时选中第一项
+func (w *Window) cursorItem() *model.ClipboardItem {
+	list := w.currentList()
+	if list == nil {
+		return nil
+	}
+	if list.Cursor() < 0 {
+		list.SetCursor(0)
+	}
+	return list.CursorItem()
+}
+
+// pasteCursorItem 将当前项写回剪贴板
+func (w *Window) pasteCursorItem() {
+	if item := w.cursorItem(); item != nil {
+		w.pasteItem(item)
+	}
+}
+
+// deleteCursorItem 删除当前项，之后当前项停留在原位置
+func (w *Window) deleteCursorItem() {
+	item := w.cursorItem()
+	if item == nil {
+		return
+	}
+	pos := w.currentList().Cursor()
+	w.deleteItem(item.ID)
+	w.restoreCursor(pos)
+}
+
+// toggleCursorFavorite 切换当前项的收藏状态
+func (w *Window) toggleCursorFavorite() {
+	item := w.cursorItem()
+	if item == nil {
+		return
+	}
+	pos := w.currentList().Cursor()
+	w.toggleFavorite(item.ID)
+	w.restoreCursor(pos)
+}
+
+// restoreCursor 刷新列表后恢复当前项位置
+func (w *Window) restoreCursor(pos int) {
+	if list := w.currentList(); list != nil {
+		list.SetCursor(pos)
+	}
+}
+
+// focusSearch 切换到历史记录页并聚焦搜索框
+func (w *Window) focusSearch() {
+	w.contentTabs.SelectIndex(0)
+	w.Canvas().Focus(w.searchBar)
+}
+
+// switchTab 切换到相邻的标签页（循环）
+func (w *Window) switchTab(delta int) {
+	n := len(w.contentTabs.Items)
+	if n == 0 {
+		return
+	}
+	w.contentTabs.SelectIndex(((w.contentTabs.SelectedIndex()+delta)%n + n) % n)
+}
+
+// openSettings 切换到设置页
+func (w *Window) openSettings() {
+	if w.settingsPanel != nil {
+		w.contentTabs.SelectIndex(len(w.contentTabs.Items) - 1)
+	}
+}
+
+// deleteItem 删除项并记录撤销；图片文件随项删除，无法撤销
+func (w *Window) deleteItem(id string) {
+	item := w.findItem(id)
+	if _, err := w.storage.DeleteItem(id); err != nil {
+		log.Printf("删除失败: %v", err)
+		return
+	}
+	if item != nil && item.Type != model.TypeImage {
+		restored := restorableCopy(item)
+		w.pushUndo(func() error {
+			_, err := w.storage.ReplaceItems(nil, []*model.ClipboardItem{restored})
+			return err
+		})
+	}
+	w.refresh()
+}
+
+// toggleFavorite 切换收藏状态并记录撤销
+func (w *Window) toggleFavorite(id string) {
+	if _, err := w.storage.ToggleFavorite(id); err != nil {
+		log.Printf("切换收藏失败: %v", err)
+		return
+	}
+	w.pushUndo(func() error {
+		_, err := w.storage.ToggleFavorite(id)
+		return err
+	})
+	w.refresh()
+}
+
+// pushUndo 记录一次可撤销的操作，超过上限时丢弃最早的
+func (w *Window) pushUndo(undo func() error) {
+	w.undoStack = append(w.undoStack, undo)
+	if len(w.undoStack) > maxUndo {
+		w.undoStack = slices.Delete(w.undoStack, 0, len(w.undoStack)-maxUndo)
+	}
+}
+
+// undo 撤销最近一次删除或收藏操作
+func (w *Window) undo() {
+	if len(w.undoStack) == 0 {
+		return
+	}
+	undo := w.undoStack[len(w.undoStack)-1]
+	w.undoStack = w.undoStack[:len(w.undoStack)-1]
+	if err := undo(); err != nil {
+		dialog.ShowError(err, w.Window)
+		return
+	}
+	w.refresh()
+}
+
+// findItem 按 ID 查找项，找不到时返回 nil
+func (w *Window) findItem(id string) *model.ClipboardItem {
+	items, err := w.storage.LoadItems()
+	if err != nil {
+		return nil
+	}
+	for _, item := range items {
+		if item.ID == id {
+			return item
+		}
+	}
+	return nil
+}
+
+// restorableCopy 返回用于恢复已删除项的副本；快照随项删除，恢复后只引用原文件
+func restorableCopy(item *model.ClipboardItem) *model.ClipboardItem {
+	restored := *item
+	restored.Files = slices.Clone(item.Files)
+	for i := range restored.Files {
+		restored.Files[i].Snapshot = ""
+	}
+	return &restored
+}
@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"clipboard/model"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"log"
+	"strings"
+)
+
+// trayFavoritesMax 托盘收藏子菜单最多显示的项数
+const trayFavoritesMax = 20
+
+// SetupTray 显示系统托盘图标，之后关闭窗口只最小化到托盘；平台不支持托盘时返回 false。
+// 可重复调用以更新最近项数量
+func (w *Window) SetupTray(recentItems int) bool {
+	desk, ok := w.app.(desktop.App)
+	if !ok {
+		return false
+	}
+
+	w.trayRecent = recentItems
+	if w.tray == nil {
+		w.tray = desk
+		desk.SetSystemTrayIcon(theme.ContentPasteIcon())
+		w.SetCloseIntercept(func() {
+			w.Hide()
+		})
+	}
+
+	items, err := w.storage.LoadItems()
+	if err != nil {
+		log.Printf("加载托盘菜单数据失败: %v", err)
+	}
+	w.refreshTray(items)
+	return true
+}
+
+// refreshTray 按历史记录重建托盘菜单
+func (w *Window) refreshTray(items []*model.ClipboardItem) {
+	if w.tray == nil {
+		return
+	}
+
+	_, items = splitSnippets(items)
+	recent := append([]*model.ClipboardItem(nil), items...)
+	model.SortItems(recent, model.SortRecent)
+
+	menu := fyne.NewMenu("剪贴板历史")
+	for i, item := range recent {
+		if i >= w.trayRecent {
+			break
+		}
+		menu.Items = append(menu.Items, w.trayItem(item))
+	}
+	if len(recent) == 0 {
+		empty := fyne.NewMenuItem("暂无历史记录", nil)
+		empty.Disabled = true
+		menu.Items = append(menu.Items, empty)
+	}
+
+	// 收藏子菜单
+	favorites := fyne.NewMenu("")
+	for _, item := range recent {
+		if item.IsFavorite && len(favorites.Items) < trayFavoritesMax {
+			favorites.Items = append(favorites.Items, w.trayItem(item))
+		}
+	}
+	favoritesItem := fyne.NewMenuItem("收藏", nil)
+	favoritesItem.ChildMenu = favorites
+	favoritesItem.Disabled = len(favorites.Items) == 0
+
+	// 暂停捕获子菜单
+	paused, _ := w.pauser.CapturePaused()
+	pauseItem := fyne.NewMenuItem("暂停捕获", nil)
+	if paused {
+		pauseItem.Label = "捕获已暂停"
+	}
+	pauseItem.ChildMenu = fyne.NewMenu("", w.pauseMenuItems(paused)...)
+
+	pickItem := fyne.NewMenuItem("快速粘贴", func() {
+		w.ShowQuickPaste()
+	})
+	openItem := fyne.NewMenuItem("打开窗口", func() {
+		w.ShowSearch("")
+	})
+	quitItem := fyne.NewMenuItem("退出", func() {
+		w.app.Quit()
+	})
+	quitItem.IsQuit = true
+
+	menu.Items = append(menu.Items,
+		fyne.NewMenuItemSeparator(),
+		favoritesItem,
+		pauseItem,
+		fyne.NewMenuItemSeparator(),
+		pickItem,
+		openItem,
+		quitItem,
+	)
+	w.tray.SetSystemTrayMenu(menu)
+}
+
+// trayItem 托盘中的历史项，点击后写回剪贴板
+func (w *Window) trayItem(item *model.ClipboardItem) *fyne.MenuItem {
+	label := strings.Join(strings.Fields(previewText(item)), " ")
+	if item.Type == model.TypeFile {
+		label = "[文件] " + label
+	}
+	return fyne.NewMenuItem(label, func() {
+		w.pasteItem(item)
+	})
+}
@@ -1,15 +1,25 @@
 package ui
 
 import (
+	"clipboard/classify"
 	"clipboard/config"
+	"clipboard/hotkey"
+	"clipboard/imageproc"
 	"clipboard/model"
+	"clipboard/snippet"
+	"clipboard/stats"
 	"clipboard/storage"
+	"clipboard/transform"
 	"clipboard/ui/component"
+	"fmt"
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
 	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
 	"log"
-	"sort"
+	"time"
 )
 
 // Window 应用主窗口
@@ -21,9 +31,39 @@ type Window struct {
 	searchBar      *component.SearchBar
 	settingsPanel  *component.SettingsPanel
 	contentTabs    *container.AppTabs
-	onSaveSettings func(*config.StorageConfig)
+	onSaveSettings func(*config.AppConfig)
 	clipboard      ClipboardSetter        // 用于设置剪贴板内容的接口
+	pauser         CapturePauser          // 用于暂停/恢复捕获的接口
+	presets        PresetSaver            // 用于保存转换预设的接口
 	favoriteList   *component.HistoryList // 新增收藏列表字段
+	snippetList    *component.HistoryList // 片段列表
+	queue          QueueController        // 用于控制粘贴队列的接口
+	selection      *component.Selection   // 多选状态
+	selectionLabel *widget.Label          // 已选数量提示
+	selectionBar   fyne.CanvasObject      // 多选操作栏，非多选模式时隐藏
+	selectBtn      *widget.Button         // 多选模式切换按钮
+	pauseBtn       *widget.Button         // 暂停捕获按钮
+	statusBox      *fyne.Container        // 暂停状态与粘贴队列状态栏
+	sortMode       model.SortMode         // 列表排序方式，为空时使用默认排序
+	tray           desktop.App            // 系统托盘，未启用时为空
+	trayRecent     int                    // 托盘菜单中显示的最近项数量
+	quickPaste     *QuickPaste            // 快速粘贴窗口
+	hotkeyFallback fyne.Shortcut          // 全局快捷键注册失败时的窗口内快捷键
+	hotkeyAction   func()                 // 全局快捷键的动作
+	shortcuts      []fyne.Shortcut        // 已绑定到画布的快捷键
+	bindings       map[hotkey.Combo]config.ShortcutAction
+	undoStack      []func() error // 可撤销的删除、收藏操作，最近的在最后
+}
+
+// sortOptions 排序方式选项；默认排序下浏览按时间、搜索按综合得分
+var sortOptions = []struct {
+	mode  model.SortMode
+	label string
+}{
+	{"", "默认排序"},
+	{model.SortRecent, "最近复制"},
+	{model.SortFrequent, "最常使用"},
+	{model.SortFrecency, "综合排序"},
 }
 
 func (w *Window) performSearch(keyword string) {
@@ -33,23 +73,62 @@ func (w *Window) performSearch(keyword string) {
 		items = []*model.ClipboardItem{}
 	}
 
+	snippets, items := splitSnippets(items)
+	if w.sortMode != "" {
+		model.SortItems(items, w.sortMode)
+	}
 	favorites, normal := splitItemsByFavorite(items)
 
 	w.historyList.UpdateItems(normal)
 	w.favoriteList.UpdateItems(favorites)
+	w.snippetList.UpdateItems(snippets)
 }
 
 // ClipboardSetter 剪贴板设置接口
 type ClipboardSetter interface {
 	SetContent(item *model.ClipboardItem) error
+	// SetTransformedContent 按转换链处理文本后写入剪贴板
+	SetTransformedContent(item *model.ClipboardItem, steps []string) error
+	// SetSnippetContent 展开片段占位符后写入剪贴板
+	SetSnippetContent(item *model.ClipboardItem, inputs map[string]string) error
+	// SetConvertedImage 转换格式、缩放或压缩图片后写入剪贴板
+	SetConvertedImage(item *model.ClipboardItem, opts imageproc.Options) error
+}
+
+// PresetSaver 转换预设保存接口
+type PresetSaver interface {
+	SaveTransformPreset(preset config.TransformPreset) error
+}
+
+// QueueController 粘贴队列控制接口
+type QueueController interface {
+	StartPasteQueue(items []*model.ClipboardItem, lifo bool) error
+	AdvancePasteQueue() error
+	ClearPasteQueue()
+	PasteQueue() (current *model.ClipboardItem, pending []*model.ClipboardItem)
+}
+
+// Controller 窗口依赖的应用层操作
+type Controller interface {
+	ClipboardSetter
+	CapturePauser
+	PresetSaver
+	QueueController
+}
+
+// CapturePauser 捕获暂停控制接口
+type CapturePauser interface {
+	PauseCapture(d time.Duration)
+	ResumeCapture()
+	CapturePaused() (bool, time.Time)
 }
 
 // NewWindow 创建主窗口
 func NewWindow(
 	app fyne.App,
 	storage storage.Storage,
-	clipboard ClipboardSetter,
-	onSaveSettings func(*config.StorageConfig),
+	controller Controller,
+	onSaveSettings func(*config.AppConfig),
 ) *Window {
 	win := app.NewWindow("剪贴板历史管理器")
 	win.Resize(fyne.NewSize(600, 400))
@@ -58,141 +137,601 @@ func NewWindow(
 		Window:         win,
 		app:            app,
 		storage:        storage,
-		clipboard:      clipboard,
+		clipboard:      controller,
+		pauser:         controller,
+		presets:        controller,
+		queue:          controller,
+		selection:      component.NewSelection(),
 		onSaveSettings: onSaveSettings,
 	}
+	w.selection.OnChange = w.updateSelectionLabel
+	w.quickPaste = NewQuickPaste(app, storage, controller)
+
+	// 窗口内快捷键；没有控件获得焦点时方向键与回车操作当前列表
+	cfg, err := config.Load()
+	if err != nil {
+		log.Printf("加载快捷键配置失败: %v", err)
+		w.applyShortcuts(config.DefaultShortcuts)
+	} else {
+		w.applyShortcuts(cfg.Shortcuts)
+	}
+	win.Canvas().SetOnTypedKey(w.handleTypedKey)
 
 	// 初始化UI
-	w.rebuildFullUI()
+	w.buildUI()
 
 	return w
 }
 
-// 全量重建UI
-func (w *Window) rebuildFullUI() {
-	// 1. 销毁旧UI引用
-	w.historyList = nil
-	w.favoriteList = nil
-	w.searchBar = nil
-	w.settingsPanel = nil
-	w.contentTabs = nil
-
-	// 2. 重新加载最新数据
-	items, err := w.storage.LoadItems()
-	if err != nil {
-		log.Printf("重建UI加载数据失败: %v", err)
-		items = []*model.ClipboardItem{}
+// buildUI 创建界面，只在创建窗口时调用一次；之后数据变化由 refresh 原地更新，
+// 保留当前标签页、搜索词和设置页中未保存的修改
+func (w *Window) buildUI() {
+	w.searchBar = component.NewSearchBar(func(text string) {
+		w.performSearch(text)
+	})
+	w.searchBar.OnNavigate = w.moveCursor
+	w.searchBar.OnShortcut = w.handleShortcut
+	w.searchBar.OnSubmitted = func(string) {
+		w.pasteCursorItem()
 	}
 
-	// 3. 分离收藏项和普通项（重新计算）
-	favoriteItems := []*model.ClipboardItem{}
-	normalItems := []*model.ClipboardItem{}
-	for _, item := range items {
-		if item.IsFavorite {
-			favoriteItems = append(favoriteItems, item)
-		} else {
-			normalItems = append(normalItems, item)
-		}
-	}
+	// 普通历史、收藏与片段列表
+	w.historyList = component.NewHistoryList(nil, w.pasteItem, w.toggleFavorite, w.deleteItem)
+	w.favoriteList = component.NewHistoryList(nil, w.pasteItem, w.toggleFavorite, w.deleteItem)
+	w.snippetList = component.NewHistoryList(nil, w.pasteItem, w.toggleFavorite, w.deleteItem)
 
-	// 4. 重建搜索框（新实例）
-	if w.searchBar == nil {
-		w.searchBar = component.NewSearchBar(func(text string) {
-			w.performSearch(text)
-		})
-	}
+	// 多选状态（各列表共用）
+	w.historyList.Selection = w.selection
+	w.favoriteList.Selection = w.selection
+	w.snippetList.Selection = w.selection
 
-	// 新增：主动将焦点设置到搜索框
-	//fyne.Do(func() {
-	//	if canvas := fyne.CurrentApp().Driver().CanvasForObject(w.searchBar); canvas != nil {
-	//		canvas.Focus(w.searchBar) // 强制让搜索框获取焦点
-	//	}
-	//})
-	// 5. 重建普通历史列表（新实例+重新绑定回调）
-	w.historyList = component.NewHistoryList(
-		normalItems,
-		func(item *model.ClipboardItem) {
-			w.clipboard.SetContent(item)
-		},
-		func(id string) {
-			// 收藏变更后触发全量重建
-			_, err := w.storage.ToggleFavorite(id)
-			if err == nil {
-				w.rebuildFullUI()
-			}
-		},
-		func(id string) {
-			// 删除后触发全量重建
-			_, err := w.storage.DeleteItem(id)
-			if err == nil {
-				w.rebuildFullUI()
-			} else {
-				log.Printf("删除失败: %v", err)
-			}
-		},
-	)
+	// 粘贴转换菜单（各列表共用）
+	w.setupPasteAs(w.historyList)
+	w.setupPasteAs(w.favoriteList)
+	w.setupPasteAs(w.snippetList)
 
-	// 6. 重建收藏列表（新实例+重新绑定回调）
-	w.favoriteList = component.NewHistoryList(
-		favoriteItems,
-		func(item *model.ClipboardItem) {
-			w.clipboard.SetContent(item)
-		},
-		func(id string) {
-			// 收藏变更后触发全量重建
-			_, err := w.storage.ToggleFavorite(id)
-			if err == nil {
-				w.rebuildFullUI()
-			}
-		},
-		func(id string) {
-			// 删除后触发全量重建
-			_, err := w.storage.DeleteItem(id)
-			if err == nil {
-				w.rebuildFullUI()
-			}
-		},
-	)
+	// 随状态变化的控件放在容器中，由 refresh 替换
+	w.selectBtn = w.newSelectButton()
+	w.pauseBtn = w.newPauseButton()
+	w.statusBox = container.NewVBox()
+	w.selectionBar = w.newSelectionBar()
 
-	// 7. 重建主内容区域（新容器）
 	historyContent := container.NewBorder(
-		w.searchBar,
-		nil, nil, nil,
+		container.NewVBox(
+			container.NewBorder(nil, nil, nil,
+				container.NewHBox(w.newSortSelect(), w.selectBtn, w.pauseBtn),
+				w.searchBar),
+			w.statusBox,
+		),
+		w.selectionBar,
+		nil, nil,
 		w.historyList,
 	)
 
-	// 8. 重建收藏内容区域（新容器）
 	favoriteContent := container.NewBorder(
 		nil, nil, nil, nil,
 		w.favoriteList,
 	)
 
-	// 9. 重建设置面板（新实例+重新加载配置）
-	//if w.settingsPanel == nil {
-	//	cfg, _ := config.Load()
-	//	w.settingsPanel = component.NewSettingsPanel(w.Window, &cfg.Storage, func(newCfg *config.StorageConfig) {
-	//		w.onSaveSettings(newCfg)
-	//	})
-	//}
+	snippetContent := container.NewBorder(
+		widget.NewButtonWithIcon("新建片段", theme.ContentAddIcon(), w.showNewSnippet),
+		nil, nil, nil,
+		w.snippetList,
+	)
+
+	// 设置面板
+	cfg, err := config.Load()
+	if err != nil {
+		log.Printf("加载配置失败: %v", err)
+	} else {
+		w.settingsPanel = component.NewSettingsPanel(w.Window, cfg, func(newCfg *config.AppConfig) {
+			w.applyShortcuts(newCfg.Shortcuts)
+			w.onSaveSettings(newCfg)
+		})
+	}
+
+	// 统计页（切换到该页时才计算）
+	statsContent := container.NewStack(widget.NewLabel("加载中..."))
+	statsTab := container.NewTabItemWithIcon("统计", theme.InfoIcon(), statsContent)
 
-	// 10. 重建标签页（新容器）
 	w.contentTabs = container.NewAppTabs(
 		container.NewTabItemWithIcon("历史记录", theme.HistoryIcon(), historyContent),
 		container.NewTabItemWithIcon("我的收藏", theme.ConfirmIcon(), favoriteContent),
-		//container.NewTabItemWithIcon("设置", theme.SettingsIcon(), w.settingsPanel),
+		container.NewTabItemWithIcon("片段", theme.DocumentCreateIcon(), snippetContent),
+		statsTab,
 	)
+	w.contentTabs.OnSelected = func(tab *container.TabItem) {
+		if tab == statsTab {
+			w.refreshStats(statsContent)
+		}
+	}
+	if w.settingsPanel != nil {
+		w.contentTabs.Append(container.NewTabItemWithIcon("设置", theme.SettingsIcon(), container.NewVScroll(w.settingsPanel)))
+	}
 
-	// 11. 重新设置主内容（销毁旧UI树）
 	w.SetContent(w.contentTabs)
-	log.Println("UI全量重建完成")
+	w.refresh()
 }
 
-// 辅助函数：分离收藏项和普通项
-func splitItemsByFavorite(items []*model.ClipboardItem) (favorites, normal []*model.ClipboardItem) {
-	sort.Slice(items, func(i, j int) bool {
-		return items[i].Timestamp.After(items[j].Timestamp)
+// refresh 重新加载数据并原地更新列表、状态栏与托盘菜单
+func (w *Window) refresh() {
+	items, err := w.storage.LoadItems()
+	if err != nil {
+		log.Printf("刷新界面加载数据失败: %v", err)
+		items = []*model.ClipboardItem{}
+	}
+
+	// 同步刷新托盘菜单
+	w.refreshTray(items)
+
+	// 转换预设可能已更新
+	if cfg, err := config.Load(); err == nil {
+		w.historyList.Presets = cfg.TransformPresets
+		w.favoriteList.Presets = cfg.TransformPresets
+		w.snippetList.Presets = cfg.TransformPresets
+	}
+
+	// 有搜索词时保持搜索结果
+	if keyword := w.searchBar.Text; keyword != "" {
+		w.performSearch(keyword)
+	} else {
+		// 分离片段、收藏项和普通项
+		snippetItems, items := splitSnippets(items)
+		if w.sortMode != "" {
+			model.SortItems(items, w.sortMode)
+		}
+		favoriteItems := []*model.ClipboardItem{}
+		normalItems := []*model.ClipboardItem{}
+		for _, item := range items {
+			if item.IsFavorite {
+				favoriteItems = append(favoriteItems, item)
+			} else {
+				normalItems = append(normalItems, item)
+			}
+		}
+		w.historyList.UpdateItems(normalItems)
+		w.favoriteList.UpdateItems(favoriteItems)
+		w.snippetList.UpdateItems(snippetItems)
+	}
+
+	// 多选、暂停与粘贴队列状态
+	if w.selection.Active {
+		w.selectBtn.SetIcon(theme.CheckButtonCheckedIcon())
+		w.selectionBar.Show()
+	} else {
+		w.selectBtn.SetIcon(theme.CheckButtonIcon())
+		w.selectionBar.Hide()
+	}
+	w.updateSelectionLabel()
+	if paused, _ := w.pauser.CapturePaused(); paused {
+		w.pauseBtn.SetIcon(theme.MediaPlayIcon())
+	} else {
+		w.pauseBtn.SetIcon(theme.MediaPauseIcon())
+	}
+	w.statusBox.Objects = []fyne.CanvasObject{w.newPauseStatus(), w.newQueuePanel()}
+	w.statusBox.Refresh()
+}
+
+// SetStorage 设置保存后存储被重建时替换窗口与快速粘贴窗口使用的存储
+func (w *Window) SetStorage(store storage.Storage) {
+	w.storage = store
+	w.quickPaste.SetStorage(store)
+	w.undoStack = nil // 撤销操作引用旧存储中的项
+	w.refresh()
+}
+
+// refreshStats 重新计算统计数据并刷新统计页
+func (w *Window) refreshStats(content *fyne.Container) {
+	var view fyne.CanvasObject
+	report, err := stats.NewService(w.storage).Compute()
+	if err != nil {
+		log.Printf("计算统计数据失败: %v", err)
+		view = widget.NewLabel("统计数据加载失败")
+	} else {
+		view = container.NewVScroll(component.NewStatsView(report))
+	}
+
+	refresh := widget.NewButtonWithIcon("刷新", theme.ViewRefreshIcon(), func() {
+		w.refreshStats(content)
 	})
+	content.Objects = []fyne.CanvasObject{container.NewBorder(container.NewHBox(refresh), nil, nil, nil, view)}
+	content.Refresh()
+}
+
+// pasteConvertedImage 转换图片后写回剪贴板
+func (w *Window) pasteConvertedImage(item *model.ClipboardItem, opts imageproc.Options) {
+	if err := w.clipboard.SetConvertedImage(item, opts); err != nil {
+		dialog.ShowError(err, w.Window)
+	}
+}
+
+// pasteItem 将项写回剪贴板；片段含输入占位符时先弹出输入对话框
+func (w *Window) pasteItem(item *model.ClipboardItem) {
+	if item.Type != model.TypeSnippet {
+		w.clipboard.SetContent(item)
+		return
+	}
 
+	labels := snippet.Inputs(item.Content)
+	if len(labels) == 0 {
+		if err := w.clipboard.SetSnippetContent(item, nil); err != nil {
+			dialog.ShowError(err, w.Window)
+		}
+		return
+	}
+
+	component.ShowSnippetInputDialog(w.Window, labels, func(inputs map[string]string) {
+		if err := w.clipboard.SetSnippetContent(item, inputs); err != nil {
+			dialog.ShowError(err, w.Window)
+		}
+	})
+}
+
+// showNewSnippet 弹出新建片段对话框
+func (w *Window) showNewSnippet() {
+	component.ShowSnippetEditor(w.Window, func(content string) {
+		item := model.NewClipboardItem(model.TypeSnippet, content, "")
+		if _, err := w.storage.AddItem(item); err != nil {
+			dialog.ShowError(err, w.Window)
+			return
+		}
+		w.refresh()
+	})
+}
+
+// setupPasteAs 为列表配置"转换后粘贴"菜单及编辑、预览等项操作
+func (w *Window) setupPasteAs(list *component.HistoryList) {
+	if cfg, err := config.Load(); err == nil {
+		list.Presets = cfg.TransformPresets
+	}
+	list.OnPasteAs = w.pasteTransformed
+	list.OnSplit = w.splitItem
+	list.OnEdit = w.showItemDetail
+	list.OnPreview = func(item *model.ClipboardItem) {
+		component.ShowImagePreview(w.Window, item.ImagePath)
+	}
+	list.OnImagePasteAs = w.pasteConvertedImage
+	list.OnCustomImageConvert = func(item *model.ClipboardItem) {
+		component.ShowImageConvertDialog(w.Window, func(opts imageproc.Options) {
+			w.pasteConvertedImage(item, opts)
+		})
+	}
+	list.OnCustomTransform = func(item *model.ClipboardItem) {
+		component.ShowTransformDialog(w.Window, func(steps []string, presetName string) {
+			if presetName != "" {
+				preset := config.TransformPreset{Name: presetName, Steps: steps}
+				if err := w.presets.SaveTransformPreset(preset); err != nil {
+					dialog.ShowError(err, w.Window)
+				}
+			}
+			w.pasteTransformed(item, steps)
+			if presetName != "" {
+				w.refresh()
+			}
+		})
+	}
+}
+
+// pasteTransformed 转换后写入剪贴板，失败时提示错误
+func (w *Window) pasteTransformed(item *model.ClipboardItem, steps []string) {
+	if err := w.clipboard.SetTransformedContent(item, steps); err != nil {
+		dialog.ShowError(err, w.Window)
+	}
+}
+
+// newSortSelect 创建排序方式选择器
+func (w *Window) newSortSelect() *widget.Select {
+	var labels []string
+	for _, o := range sortOptions {
+		labels = append(labels, o.label)
+	}
+	sel := widget.NewSelect(labels, nil)
+	// 直接设置当前值，避免 SetSelected 触发回调导致重复重建
+	for _, o := range sortOptions {
+		if o.mode == w.sortMode {
+			sel.Selected = o.label
+		}
+	}
+	sel.OnChanged = func(label string) {
+		for _, o := range sortOptions {
+			if o.label == label && o.mode != w.sortMode {
+				w.sortMode = o.mode
+				w.refresh()
+			}
+		}
+	}
+	return sel
+}
+
+// newSelectButton 创建多选模式切换按钮
+func (w *Window) newSelectButton() *widget.Button {
+	btn := widget.NewButtonWithIcon("", theme.CheckButtonIcon(), func() {
+		w.selection.Active = !w.selection.Active
+		if !w.selection.Active {
+			w.selection.Clear()
+		}
+		w.refresh()
+	})
+	btn.Importance = widget.LowImportance
+	return btn
+}
+
+// newSelectionBar 创建多选操作栏，非多选模式时隐藏
+func (w *Window) newSelectionBar() fyne.CanvasObject {
+	w.selectionLabel = widget.NewLabel("")
+	w.updateSelectionLabel()
+
+	mode := widget.NewRadioGroup([]string{"FIFO", "LIFO"}, nil)
+	mode.Horizontal = true
+	mode.SetSelected("FIFO")
+
+	queueBtn := widget.NewButtonWithIcon("加入粘贴队列", theme.ContentPasteIcon(), func() {
+		items := w.selectedItems()
+		if len(items) == 0 {
+			dialog.ShowInformation("粘贴队列", "请先选择要排队的项", w.Window)
+			return
+		}
+		if err := w.queue.StartPasteQueue(items, mode.Selected == "LIFO"); err != nil {
+			dialog.ShowError(err, w.Window)
+			return
+		}
+		w.selection.Active = false
+		w.selection.Clear()
+		w.refresh()
+	})
+
+	joinBtn := widget.NewButtonWithIcon("合并", theme.ContentAddIcon(), w.joinSelected)
+
+	bar := container.NewHBox(w.selectionLabel, mode, queueBtn, joinBtn)
+	if !w.selection.Active {
+		bar.Hide()
+	}
+	return bar
+}
+
+// joinSelected 合并选中的文本项
+func (w *Window) joinSelected() {
+	items := w.selectedItems()
+	if len(items) < 2 {
+		dialog.ShowInformation("合并", "请至少选择两个文本项", w.Window)
+		return
+	}
+	for _, item := range items {
+		if item.Type != model.TypeText {
+			dialog.ShowInformation("合并", "只能合并文本项", w.Window)
+			return
+		}
+	}
+
+	component.ShowJoinDialog(w.Window, len(items), func(opts transform.JoinOptions, keepOriginals bool) {
+		texts := make([]string, len(items))
+		var removeIDs []string
+		for i, item := range items {
+			texts[i] = item.Content
+			if !keepOriginals {
+				removeIDs = append(removeIDs, item.ID)
+			}
+		}
+
+		merged := derivedItem(transform.Join(texts, opts), time.Now(), items...)
+		if _, err := w.storage.ReplaceItems(removeIDs, []*model.ClipboardItem{merged}); err != nil {
+			dialog.ShowError(err, w.Window)
+			return
+		}
+		w.selection.Active = false
+		w.selection.Clear()
+		w.refresh()
+	})
+}
+
+// splitItem 将多行文本项按行拆分为多个历史项
+func (w *Window) splitItem(item *model.ClipboardItem) {
+	lines := transform.Split(item.Content)
+	if len(lines) < 2 {
+		dialog.ShowInformation("拆分", "该项只有一行内容", w.Window)
+		return
+	}
+
+	// 时间戳依次递减，使拆分后的项在列表中保持原有行序
+	now := time.Now()
+	parts := make([]*model.ClipboardItem, len(lines))
+	for i, line := range lines {
+		parts[i] = derivedItem(line, now.Add(-time.Duration(i)*time.Millisecond), item)
+	}
+
+	if _, err := w.storage.ReplaceItems([]string{item.ID}, parts); err != nil {
+		dialog.ShowError(err, w.Window)
+		return
+	}
+	w.refresh()
+}
+
+// showItemDetail 打开项详情，可编辑内容和恢复历史版本
+func (w *Window) showItemDetail(item *model.ClipboardItem) {
+	revisions, err := w.storage.Revisions(item.ID)
+	if err != nil {
+		log.Printf("加载历史版本失败: %v", err)
+	}
+
+	save := func(content string) error {
+		edited := *item
+		edited.Content = content
+		if edited.Type == model.TypeText {
+			edited.Subtype, edited.Language = classify.Text(content)
+		}
+		if _, err := w.storage.UpdateItem(&edited); err != nil {
+			return err
+		}
+		w.refresh()
+		return nil
+	}
+
+	component.ShowItemDetail(w.Window, item, revisions, component.ItemDetailActions{
+		OnSave: save,
+		OnRevert: func(rev model.Revision) error {
+			return save(rev.Content)
+		},
+	})
+}
+
+// derivedItem 由已有项生成新文本项，继承敏感标记和最早的过期时间
+func derivedItem(content string, ts time.Time, sources ...*model.ClipboardItem) *model.ClipboardItem {
+	item := model.NewClipboardItem(model.TypeText, content, "")
+	item.Timestamp = ts
+	item.Subtype, item.Language = classify.Text(content)
+	for _, src := range sources {
+		if src.Sensitive {
+			item.Sensitive = true
+		}
+		if src.ExpiresAt != nil && (item.ExpiresAt == nil || src.ExpiresAt.Before(*item.ExpiresAt)) {
+			expiresAt := *src.ExpiresAt
+			item.ExpiresAt = &expiresAt
+		}
+	}
+	return item
+}
+
+// updateSelectionLabel 更新已选数量提示
+func (w *Window) updateSelectionLabel() {
+	if w.selectionLabel != nil {
+		w.selectionLabel.SetText(fmt.Sprintf("已选 %d 项", w.selection.Len()))
+	}
+}
+
+// selectedItems 按选中顺序返回选中的项
+func (w *Window) selectedItems() []*model.ClipboardItem {
+	items, err := w.storage.LoadItems()
+	if err != nil {
+		log.Printf("加载选中项失败: %v", err)
+		return nil
+	}
+	byID := make(map[string]*model.ClipboardItem, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+
+	var selected []*model.ClipboardItem
+	for _, id := range w.selection.IDs() {
+		if item, ok := byID[id]; ok {
+			selected = append(selected, item)
+		}
+	}
+	return selected
+}
+
+// newQueuePanel 创建粘贴队列状态栏，队列为空时隐藏
+func (w *Window) newQueuePanel() fyne.CanvasObject {
+	current, pending := w.queue.PasteQueue()
+	if current == nil && len(pending) == 0 {
+		return container.NewHBox()
+	}
+
+	text := fmt.Sprintf("粘贴队列：剩余 %d 项", len(pending))
+	if current != nil {
+		text = fmt.Sprintf("粘贴队列：当前「%s」，剩余 %d 项", previewText(current), len(pending))
+	}
+	label := widget.NewLabel(text)
+	label.Truncation = fyne.TextTruncateEllipsis
+
+	nextBtn := widget.NewButtonWithIcon("下一项", theme.MediaSkipNextIcon(), w.advanceQueue)
+	stopBtn := widget.NewButtonWithIcon("结束", theme.MediaStopIcon(), func() {
+		w.queue.ClearPasteQueue()
+	})
+	return container.NewBorder(nil, nil, nil, container.NewHBox(nextBtn, stopBtn), label)
+}
+
+// advanceQueue 载入粘贴队列的下一项
+func (w *Window) advanceQueue() {
+	if err := w.queue.AdvancePasteQueue(); err != nil {
+		dialog.ShowError(err, w.Window)
+	}
+}
+
+// previewText 返回项的简短预览文本
+func previewText(item *model.ClipboardItem) string {
+	if item.Sensitive {
+		return "••••••••"
+	}
+	if item.Type == model.TypeImage {
+		return "图片"
+	}
+	text := []rune(item.Content)
+	if len(text) > 20 {
+		return string(text[:20]) + "..."
+	}
+	return string(text)
+}
+
+// newPauseButton 创建暂停捕获按钮，点击弹出暂停时长菜单（图标由 refresh 更新）
+func (w *Window) newPauseButton() *widget.Button {
+	var btn *widget.Button
+	btn = widget.NewButtonWithIcon("", theme.MediaPauseIcon(), func() {
+		paused, _ := w.pauser.CapturePaused()
+		menu := fyne.NewMenu("", w.pauseMenuItems(paused)...)
+		canvas := fyne.CurrentApp().Driver().CanvasForObject(btn)
+		pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(btn)
+		widget.ShowPopUpMenuAtPosition(menu, canvas, pos.Add(fyne.NewPos(0, btn.Size().Height)))
+	})
+	btn.Importance = widget.LowImportance
+	return btn
+}
+
+// pauseMenuItems 暂停时长与恢复捕获菜单项（暂停按钮与托盘共用），操作后刷新界面
+func (w *Window) pauseMenuItems(paused bool) []*fyne.MenuItem {
+	pause := func(d time.Duration) func() {
+		return func() {
+			w.pauser.PauseCapture(d)
+			w.refresh()
+		}
+	}
+	items := []*fyne.MenuItem{
+		fyne.NewMenuItem("暂停 15 分钟", pause(15*time.Minute)),
+		fyne.NewMenuItem("暂停 1 小时", pause(time.Hour)),
+		fyne.NewMenuItem("暂停直到手动恢复", pause(0)),
+	}
+	if paused {
+		items = append([]*fyne.MenuItem{
+			fyne.NewMenuItem("恢复捕获", func() {
+				w.pauser.ResumeCapture()
+				w.refresh()
+			}),
+			fyne.NewMenuItemSeparator(),
+		}, items...)
+	}
+	return items
+}
+
+// newPauseStatus 创建暂停状态提示，未暂停时隐藏
+func (w *Window) newPauseStatus() *widget.Label {
+	label := widget.NewLabel("")
+	label.TextStyle = fyne.TextStyle{Italic: true}
+
+	paused, until := w.pauser.CapturePaused()
+	switch {
+	case !paused:
+		label.Hide()
+	case until.IsZero():
+		label.SetText("捕获已暂停，直到手动恢复")
+	default:
+		label.SetText("捕获已暂停，将于 " + until.Format("15:04") + " 恢复")
+	}
+	return label
+}
+
+// 辅助函数：分离片段和其他项
+func splitSnippets(items []*model.ClipboardItem) (snippets, others []*model.ClipboardItem) {
+	for _, item := range items {
+		if item.Type == model.TypeSnippet {
+			snippets = append(snippets, item)
+		} else {
+			others = append(others, item)
+		}
+	}
+	return
+}
+
+// 辅助函数：分离收藏项和普通项（保持传入顺序）
+func splitItemsByFavorite(items []*model.ClipboardItem) (favorites, normal []*model.ClipboardItem) {
 	for _, item := range items {
 		if item.IsFavorite {
 			favorites = append(favorites, item)
@@ -202,11 +741,42 @@ func splitItemsByFavorite(items []*model.ClipboardItem) (favorites, normal []*mo
 	return
 }
 
-// UpdateHistory 更新历史记录
+// ShowSearch 显示并聚焦窗口，切换到历史标签页并搜索 query（为空时只聚焦搜索框）
+func (w *Window) ShowSearch(query string) {
+	w.Show()
+	w.RequestFocus()
+	if w.contentTabs != nil {
+		w.contentTabs.SelectIndex(0)
+	}
+	if query != "" {
+		w.searchBar.SetText(query)
+	}
+	w.Canvas().Focus(w.searchBar)
+}
+
+// ShowQuickPaste 打开快速粘贴窗口
+func (w *Window) ShowQuickPaste() {
+	w.quickPaste.Show()
+}
+
+// SetHotkeyFallback 设置全局快捷键注册失败时的窗口内快捷键，shortcut 为空时移除
+func (w *Window) SetHotkeyFallback(shortcut fyne.Shortcut, action func()) {
+	if w.hotkeyFallback != nil {
+		w.Canvas().RemoveShortcut(w.hotkeyFallback)
+		w.hotkeyFallback, w.hotkeyAction = nil, nil
+	}
+	if shortcut == nil {
+		return
+	}
+	w.hotkeyFallback, w.hotkeyAction = shortcut, action
+	w.Canvas().AddShortcut(shortcut, func(fyne.Shortcut) {
+		action()
+	})
+}
+
+// UpdateHistory 重新加载数据并刷新界面，保留当前标签页与设置页的编辑
 func (w *Window) UpdateHistory(_ []*model.ClipboardItem) {
-	log.Println("收到数据更新通知，触发UI全量重建")
-	// 直接调用全量重建
 	fyne.Do(func() {
-		w.rebuildFullUI()
+		w.refresh()
 	})
 }